@@ -2,15 +2,23 @@ package main
 
 import (
 	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
 	"api-stockflow/internal/security"
+	"api-stockflow/internal/usecase"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/joho/godotenv/autoload"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func main() {
@@ -38,46 +46,158 @@ func main() {
 
 	log.Println("Starting database seeding...")
 
-	passwordHash := security.NewBcryptPasswordHash()
+	passwordHash := security.NewPasswordHash(passwordHashConfig())
+
+	// Seeded passwords follow the same policy as passwords set through the API
+	passwordPolicy := usecase.NewPasswordPolicy(
+		repository.NewPasswordHistoryRepository(db),
+		passwordHash,
+		security.NewBreachedPasswords(os.Getenv("BREACHED_PASSWORDS_DIR")),
+		usecase.PasswordPolicyConfig{
+			MinLength:      envInt("PASSWORD_MIN_LENGTH", 10),
+			MinCharClasses: envInt("PASSWORD_MIN_CHAR_CLASSES", 3),
+			HistorySize:    envInt("PASSWORD_HISTORY_SIZE", 5),
+		},
+	)
+
+	seeder := &userSeeder{db: db, passwordHash: passwordHash, passwordPolicy: passwordPolicy}
 
 	// Seed admin user
-	seedUser(db, passwordHash, &domain.User{
+	seeder.seed(&domain.User{
 		ID:       "user-" + uuid.New().String(),
 		Username: "admin",
-		Password: "admin123",
 		Fullname: "Administrator",
 		Role:     domain.RoleAdmin,
-	})
+	}, "SEED_ADMIN_PASSWORD")
 
 	// Seed manager user
-	seedUser(db, passwordHash, &domain.User{
+	seeder.seed(&domain.User{
 		ID:       "user-" + uuid.New().String(),
 		Username: "manager",
-		Password: "manager123",
 		Fullname: "Manager User",
 		Role:     domain.RoleManager,
-	})
+	}, "SEED_MANAGER_PASSWORD")
 
 	// Seed staff user
-	seedUser(db, passwordHash, &domain.User{
+	seeder.seed(&domain.User{
 		ID:       "user-" + uuid.New().String(),
 		Username: "staff",
-		Password: "staff123",
 		Fullname: "Staff User",
 		Role:     domain.RoleStaff,
-	})
+	}, "SEED_STAFF_PASSWORD")
 
 	log.Println("Database seeding completed successfully!")
-	log.Println("\nDefault Users:")
-	log.Println("1. Admin    - username: admin,   password: admin123")
-	log.Println("2. Manager  - username: manager, password: manager123")
-	log.Println("3. Staff    - username: staff,   password: staff123")
 }
 
-func seedUser(db *sql.DB, passwordHash security.PasswordHash, user *domain.User) {
+// envInt returns the integer value of key or def when it is not set or invalid
+func envInt(key string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envIntRange returns the integer value of key or def when it is not set.
+// It exits when the value is invalid or outside min..max.
+func envIntRange(key string, def, min, max int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		log.Fatalf("%s must be an integer between %d and %d, got %q", key, min, max, raw)
+	}
+	return value
+}
+
+// passwordHashConfig returns the password hash settings the server uses, so
+// seeded passwords are not rehashed on their first login
+func passwordHashConfig() security.PasswordHashConfig {
+	algorithm := os.Getenv("PASSWORD_HASH_ALGORITHM")
+	if algorithm == "" {
+		algorithm = "argon2id"
+	}
+	if algorithm != "argon2id" && algorithm != "bcrypt" {
+		log.Fatalf("PASSWORD_HASH_ALGORITHM must be argon2id or bcrypt, got %q", algorithm)
+	}
+
+	defaults := security.DefaultArgon2idParams
+	return security.PasswordHashConfig{
+		Algorithm:  algorithm,
+		BcryptCost: envIntRange("BCRYPT_COST", bcrypt.DefaultCost, bcrypt.MinCost, bcrypt.MaxCost),
+		Argon2id: security.Argon2idParams{
+			Memory:      uint32(envIntRange("ARGON2_MEMORY", int(defaults.Memory), 1, math.MaxUint32)),
+			Iterations:  uint32(envIntRange("ARGON2_ITERATIONS", int(defaults.Iterations), 1, math.MaxUint32)),
+			Parallelism: uint8(envIntRange("ARGON2_PARALLELISM", int(defaults.Parallelism), 1, math.MaxUint8)),
+			SaltLength:  defaults.SaltLength,
+			KeyLength:   defaults.KeyLength,
+		},
+	}
+}
+
+// maxPasswordAttempts bounds the random passwords tried before giving up on the policy
+const maxPasswordAttempts = 100
+
+type userSeeder struct {
+	db             *sql.DB
+	passwordHash   security.PasswordHash
+	passwordPolicy usecase.PasswordPolicy
+}
+
+// password returns the password configured in key, which must pass the password
+// policy, or a random one passing it. generated reports a random password.
+func (s *userSeeder) password(user *domain.User, key string) (password string, generated bool) {
+	// The user does not exist yet, so the policy skips the history
+	candidate := &domain.User{Username: user.Username}
+
+	if password := os.Getenv(key); password != "" {
+		err := s.passwordPolicy.Check(context.Background(), key, candidate, password)
+		if err != nil {
+			log.Fatalf("%s does not meet the password policy: %s", key, policyViolations(err))
+		}
+		return password, false
+	}
+
+	for range maxPasswordAttempts {
+		password, err := security.GenerateRandomToken(12)
+		if err != nil {
+			log.Fatal("Failed to generate password:", err)
+		}
+
+		err = s.passwordPolicy.Check(context.Background(), key, candidate, password)
+		if err == nil {
+			return password, true
+		}
+		var validationErr *domain.ValidationError
+		if !errors.As(err, &validationErr) {
+			log.Fatal("Failed to check password:", err)
+		}
+	}
+
+	log.Fatalf("Failed to generate a password meeting the policy for user '%s', set %s instead", user.Username, key)
+	return "", false
+}
+
+// policyViolations lists the messages of a password policy error without the password
+func policyViolations(err error) string {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+
+	messages := make([]string, len(validationErr.Errors))
+	for i, violation := range validationErr.Errors {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (s *userSeeder) seed(user *domain.User, passwordKey string) {
 	// Check if user already exists
 	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)", user.Username).Scan(&exists)
+	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)", user.Username).Scan(&exists)
 	if err != nil {
 		log.Printf("Error checking user existence: %v\n", err)
 		return
@@ -88,8 +208,10 @@ func seedUser(db *sql.DB, passwordHash security.PasswordHash, user *domain.User)
 		return
 	}
 
+	password, generated := s.password(user, passwordKey)
+
 	// Hash password
-	hashedPassword, err := passwordHash.Hash(user.Password)
+	hashedPassword, err := s.passwordHash.Hash(password)
 	if err != nil {
 		log.Printf("Failed to hash password for user '%s': %v\n", user.Username, err)
 		return
@@ -101,11 +223,24 @@ func seedUser(db *sql.DB, passwordHash security.PasswordHash, user *domain.User)
 		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
 	`
 
-	_, err = db.Exec(query, user.ID, user.Username, hashedPassword, user.Fullname, user.Role)
+	_, err = s.db.Exec(query, user.ID, user.Username, hashedPassword, user.Fullname, user.Role)
 	if err != nil {
 		log.Printf("Failed to insert user '%s': %v\n", user.Username, err)
 		return
 	}
 
+	_, err = s.db.Exec("INSERT INTO user_roles (user_id, role) VALUES ($1, $2)", user.ID, user.Role)
+	if err != nil {
+		log.Printf("Failed to assign role to user '%s': %v\n", user.Username, err)
+		return
+	}
+
+	s.passwordPolicy.Remember(context.Background(), user.ID, hashedPassword)
+
 	log.Printf("Successfully seeded user: %s (%s)\n", user.Username, user.Role)
+
+	// Only generated passwords are shown, once and on stdout, so logs never hold them
+	if generated {
+		fmt.Printf("Generated password of user '%s': %s\n", user.Username, password)
+	}
 }
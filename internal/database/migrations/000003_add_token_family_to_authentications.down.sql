@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_authentications_family_id;
+ALTER TABLE authentications DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE authentications DROP COLUMN IF EXISTS parent_token;
+ALTER TABLE authentications DROP COLUMN IF EXISTS family_id;
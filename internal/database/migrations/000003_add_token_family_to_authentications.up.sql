@@ -0,0 +1,10 @@
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS family_id VARCHAR(50);
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS parent_token TEXT;
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP WITH TIME ZONE;
+
+-- Tokens issued before rotation existed each start their own family
+UPDATE authentications SET family_id = 'family-' || md5(token) WHERE family_id IS NULL;
+
+ALTER TABLE authentications ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_authentications_family_id ON authentications(family_id);
@@ -0,0 +1,3 @@
+ALTER TABLE authentications DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE authentications DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE authentications DROP COLUMN IF EXISTS user_agent;
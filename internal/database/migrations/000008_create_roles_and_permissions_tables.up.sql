@@ -0,0 +1,66 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(20) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(20) NOT NULL,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role, permission),
+    FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id VARCHAR(50) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    PRIMARY KEY (user_id, role),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role);
+
+INSERT INTO permissions (name, description) VALUES
+    ('user:read', 'View users'),
+    ('user:manage', 'Create, edit and deactivate users, reset passwords and unlock accounts'),
+    ('role:manage', 'Manage roles and role assignments'),
+    ('product:read', 'View products'),
+    ('product:write', 'Create and edit products'),
+    ('po:create', 'Create purchase orders'),
+    ('po:approve', 'Approve purchase orders')
+ON CONFLICT (name) DO NOTHING;
+
+-- Built-in roles matching the former hard-coded role checks
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Full access to every feature'),
+    ('manager', 'Full access to master data, transactions and approvals'),
+    ('staff', 'Can input transactions')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('manager', 'user:read'),
+    ('manager', 'product:read'),
+    ('manager', 'product:write'),
+    ('manager', 'po:create'),
+    ('manager', 'po:approve'),
+    ('staff', 'product:read'),
+    ('staff', 'po:create'),
+    ('staff', 'po:approve')
+ON CONFLICT DO NOTHING;
+
+-- Existing users keep their role
+INSERT INTO user_roles (user_id, role)
+SELECT id, role FROM users WHERE role IN (SELECT name FROM roles)
+ON CONFLICT DO NOTHING;
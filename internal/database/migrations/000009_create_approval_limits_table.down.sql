@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_approval_limits_user_id;
+DROP INDEX IF EXISTS idx_approval_limits_scope;
+DROP TABLE IF EXISTS approval_limits;
+DELETE FROM permissions WHERE name = 'approval:manage';
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS approval_limits (
+    id VARCHAR(50) PRIMARY KEY,
+    role VARCHAR(20),
+    user_id VARCHAR(50),
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    document_type VARCHAR(50) NOT NULL DEFAULT '',
+    max_amount NUMERIC(20, 2),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK ((role IS NULL) <> (user_id IS NULL)),
+    CHECK (max_amount IS NULL OR max_amount >= 0)
+);
+
+-- One limit per role or user for each currency and document type combination
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_limits_scope
+    ON approval_limits (COALESCE(role, ''), COALESCE(user_id, ''), currency, document_type);
+CREATE INDEX IF NOT EXISTS idx_approval_limits_user_id ON approval_limits(user_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('approval:manage', 'Manage approval limits')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'approval:manage')
+ON CONFLICT DO NOTHING;
+
+-- Former hard-coded limits: admin and manager unlimited, staff up to 10,000,000
+INSERT INTO approval_limits (id, role, max_amount) VALUES
+    ('limit-admin', 'admin', NULL),
+    ('limit-manager', 'manager', NULL),
+    ('limit-staff', 'staff', 10000000)
+ON CONFLICT DO NOTHING;
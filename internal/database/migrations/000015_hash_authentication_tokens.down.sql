@@ -0,0 +1,12 @@
+-- Raw tokens cannot be recovered from their hash, every user has to login again
+DELETE FROM authentications;
+
+DROP INDEX IF EXISTS idx_authentications_revoked_at;
+DROP INDEX IF EXISTS idx_authentications_expires_at;
+ALTER TABLE authentications DROP CONSTRAINT IF EXISTS authentications_pkey;
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS token TEXT NOT NULL;
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS parent_token TEXT;
+ALTER TABLE authentications DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE authentications DROP COLUMN IF EXISTS parent_token_hash;
+ALTER TABLE authentications DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE authentications ADD PRIMARY KEY (token);
@@ -0,0 +1,32 @@
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64);
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS parent_token_hash VARCHAR(64);
+ALTER TABLE authentications ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Replace the raw tokens with their SHA-256 hash, the key refresh tokens are looked up by
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'authentications' AND column_name = 'token'
+    ) THEN
+        UPDATE authentications SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex')
+        WHERE token_hash IS NULL;
+
+        UPDATE authentications SET parent_token_hash = encode(sha256(convert_to(parent_token, 'UTF8')), 'hex')
+        WHERE parent_token IS NOT NULL AND parent_token_hash IS NULL;
+
+        ALTER TABLE authentications DROP CONSTRAINT IF EXISTS authentications_pkey;
+        ALTER TABLE authentications DROP COLUMN token;
+        ALTER TABLE authentications DROP COLUMN IF EXISTS parent_token;
+        ALTER TABLE authentications ADD PRIMARY KEY (token_hash);
+    END IF;
+END $$;
+
+-- Refresh tokens are valid for 30 days from their creation
+UPDATE authentications SET expires_at = COALESCE(created_at, CURRENT_TIMESTAMP) + INTERVAL '30 days'
+WHERE expires_at IS NULL;
+
+ALTER TABLE authentications ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_authentications_expires_at ON authentications(expires_at);
+CREATE INDEX IF NOT EXISTS idx_authentications_revoked_at ON authentications(revoked_at) WHERE revoked_at IS NOT NULL;
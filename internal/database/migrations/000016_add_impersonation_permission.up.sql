@@ -0,0 +1,7 @@
+INSERT INTO permissions (name, description) VALUES
+    ('user:impersonate', 'Act as another user to reproduce what they see')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'user:impersonate')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS products (
+    id VARCHAR(50) PRIMARY KEY,
+    sku VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    unit_of_measure VARCHAR(20) NOT NULL,
+    barcode VARCHAR(64) NOT NULL DEFAULT '',
+    cost NUMERIC(20, 4) NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT products_sku_key UNIQUE (sku),
+    CHECK (cost >= 0)
+);
+
+-- Products without a barcode do not collide
+CREATE UNIQUE INDEX IF NOT EXISTS products_barcode_key ON products(barcode) WHERE barcode <> '';
+CREATE INDEX IF NOT EXISTS idx_products_name ON products(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
@@ -0,0 +1,12 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Products keep the name of their category, the hierarchy is lost
+UPDATE products p SET category = c.name
+FROM categories c
+WHERE c.id = p.category_id;
+
+DROP INDEX IF EXISTS idx_products_category_id;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+
+DROP TABLE IF EXISTS categories;
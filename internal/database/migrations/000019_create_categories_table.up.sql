@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id VARCHAR(50) PRIMARY KEY,
+    parent_id VARCHAR(50),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Categories holding subcategories cannot be deleted
+    FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE RESTRICT,
+    CHECK (parent_id <> id)
+);
+
+-- Sibling names are unique, the same name may appear under different parents
+CREATE UNIQUE INDEX IF NOT EXISTS categories_sibling_name_key ON categories (COALESCE(parent_id, ''), LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id VARCHAR(50);
+
+-- Former free-text categories become root categories
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'products' AND column_name = 'category'
+    ) THEN
+        INSERT INTO categories (id, name)
+        SELECT 'category-' || md5(LOWER(category)), MIN(category)
+        FROM products
+        WHERE category <> ''
+        GROUP BY LOWER(category)
+        ON CONFLICT DO NOTHING;
+
+        UPDATE products SET category_id = 'category-' || md5(LOWER(category))
+        WHERE category <> '' AND category_id IS NULL;
+
+        DROP INDEX IF EXISTS idx_products_category;
+        ALTER TABLE products DROP COLUMN category;
+    END IF;
+END $$;
+
+-- Categories holding products cannot be deleted
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_category_id_fkey;
+ALTER TABLE products ADD CONSTRAINT products_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_units;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_unit_of_measure_fkey;
+DROP TABLE IF EXISTS units_of_measure;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS units_of_measure (
+    code VARCHAR(20) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    decimals SMALLINT NOT NULL DEFAULT 0 CHECK (decimals BETWEEN 0 AND 6),
+    rounding VARCHAR(10) NOT NULL DEFAULT 'reject' CHECK (rounding IN ('reject', 'half_up', 'up', 'down')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO units_of_measure (code, name, decimals) VALUES
+    ('PCS', 'Pieces', 0),
+    ('BOX', 'Box', 0),
+    ('CTN', 'Carton', 0),
+    ('PACK', 'Pack', 0),
+    ('KG', 'Kilogram', 3),
+    ('G', 'Gram', 0),
+    ('L', 'Liter', 3),
+    ('M', 'Meter', 2)
+ON CONFLICT DO NOTHING;
+
+-- Units already used by products become units of the master
+INSERT INTO units_of_measure (code, name)
+SELECT DISTINCT unit_of_measure, unit_of_measure FROM products
+ON CONFLICT DO NOTHING;
+
+-- The unit of measure of a product is its base unit
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_unit_of_measure_fkey;
+ALTER TABLE products ADD CONSTRAINT products_unit_of_measure_fkey
+    FOREIGN KEY (unit_of_measure) REFERENCES units_of_measure(code) ON DELETE RESTRICT;
+
+-- Other units a product is handled in, as the number of base units in one of them
+CREATE TABLE IF NOT EXISTS product_units (
+    product_id VARCHAR(50) NOT NULL,
+    uom VARCHAR(20) NOT NULL,
+    factor NUMERIC(20, 6) NOT NULL CHECK (factor > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, uom),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (uom) REFERENCES units_of_measure(code) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_units_uom ON product_units(uom);
@@ -0,0 +1,6 @@
+DELETE FROM role_permissions WHERE permission = 'warehouse:manage';
+DELETE FROM permissions WHERE name = 'warehouse:manage';
+
+DROP TABLE IF EXISTS user_warehouses;
+DROP TABLE IF EXISTS locations;
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS warehouses (
+    id VARCHAR(50) PRIMARY KEY,
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT warehouses_code_key UNIQUE (code)
+);
+
+-- Zones hold aisles and aisles hold bins; locations holding others cannot be deleted
+CREATE TABLE IF NOT EXISTS locations (
+    id VARCHAR(50) PRIMARY KEY,
+    warehouse_id VARCHAR(50) NOT NULL,
+    parent_id VARCHAR(50),
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    level VARCHAR(10) NOT NULL CHECK (level IN ('zone', 'aisle', 'bin')),
+    type VARCHAR(20) NOT NULL DEFAULT 'storage' CHECK (type IN ('receiving', 'storage', 'picking', 'quarantine')),
+    capacity NUMERIC(20, 6) CHECK (capacity IS NULL OR capacity >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT locations_code_key UNIQUE (warehouse_id, code),
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE RESTRICT,
+    FOREIGN KEY (parent_id) REFERENCES locations(id) ON DELETE RESTRICT,
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_locations_parent_id ON locations(parent_id);
+
+-- Warehouses a user may operate
+CREATE TABLE IF NOT EXISTS user_warehouses (
+    user_id VARCHAR(50) NOT NULL,
+    warehouse_id VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, warehouse_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_warehouses_warehouse_id ON user_warehouses(warehouse_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('warehouse:manage', 'Manage warehouses, locations and user assignments; operate every warehouse')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'warehouse:manage')
+ON CONFLICT DO NOTHING;
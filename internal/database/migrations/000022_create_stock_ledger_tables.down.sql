@@ -0,0 +1,7 @@
+DELETE FROM role_permissions WHERE permission IN ('stock:read', 'stock:post');
+DELETE FROM permissions WHERE name IN ('stock:read', 'stock:post');
+
+DROP TABLE IF EXISTS api_key_warehouses;
+DROP TABLE IF EXISTS stock_balances;
+DROP TABLE IF EXISTS stock_movements;
+DROP FUNCTION IF EXISTS stock_movements_append_only();
@@ -0,0 +1,100 @@
+-- Append-only ledger; every change of stock is a movement, corrections are reversing movements
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id VARCHAR(50) PRIMARY KEY,
+    sequence BIGSERIAL NOT NULL,
+    posting_id VARCHAR(50) NOT NULL,
+    product_id VARCHAR(50) NOT NULL,
+    location_id VARCHAR(50) NOT NULL,
+    warehouse_id VARCHAR(50) NOT NULL,
+    -- Signed change in the base unit of the product
+    quantity NUMERIC(20, 6) NOT NULL CHECK (quantity <> 0),
+    uom VARCHAR(20) NOT NULL,
+    -- Quantity and unit as entered, before conversion to the base unit
+    entered_quantity NUMERIC(20, 6) NOT NULL,
+    entered_uom VARCHAR(20) NOT NULL,
+    balance_after NUMERIC(20, 6) NOT NULL,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('receipt', 'issue', 'adjustment', 'transfer')),
+    reference_type VARCHAR(50) NOT NULL DEFAULT '',
+    reference_id VARCHAR(100) NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    reverses_id VARCHAR(50),
+    -- Posted by a user or by an API key, never both
+    user_id VARCHAR(50),
+    api_key_id VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT stock_movements_sequence_key UNIQUE (sequence),
+    -- A movement is reversed at most once
+    CONSTRAINT stock_movements_reverses_id_key UNIQUE (reverses_id),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE RESTRICT,
+    FOREIGN KEY (uom) REFERENCES units_of_measure(code) ON DELETE RESTRICT,
+    FOREIGN KEY (reverses_id) REFERENCES stock_movements(id) ON DELETE RESTRICT,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE RESTRICT,
+    CHECK (num_nonnulls(user_id, api_key_id) = 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_posting_id ON stock_movements(posting_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_location ON stock_movements(product_id, location_id, sequence);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_warehouse_id ON stock_movements(warehouse_id, sequence);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_reference ON stock_movements(reference_type, reference_id);
+
+CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only, post a reversal instead';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS stock_movements_append_only ON stock_movements;
+CREATE TRIGGER stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+DROP TRIGGER IF EXISTS stock_movements_no_truncate ON stock_movements;
+CREATE TRIGGER stock_movements_no_truncate
+    BEFORE TRUNCATE ON stock_movements
+    FOR EACH STATEMENT EXECUTE FUNCTION stock_movements_append_only();
+
+-- On-hand quantity per product and location, updated with every movement in the same transaction
+CREATE TABLE IF NOT EXISTS stock_balances (
+    product_id VARCHAR(50) NOT NULL,
+    location_id VARCHAR(50) NOT NULL,
+    warehouse_id VARCHAR(50) NOT NULL,
+    quantity NUMERIC(20, 6) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, location_id),
+    CONSTRAINT stock_balances_quantity_check CHECK (quantity >= 0),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_balances_location_id ON stock_balances(location_id);
+CREATE INDEX IF NOT EXISTS idx_stock_balances_warehouse_id ON stock_balances(warehouse_id);
+
+-- Warehouses an API key may operate, like user_warehouses for users
+CREATE TABLE IF NOT EXISTS api_key_warehouses (
+    api_key_id VARCHAR(50) NOT NULL,
+    warehouse_id VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (api_key_id, warehouse_id),
+    FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE,
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_warehouses_warehouse_id ON api_key_warehouses(warehouse_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('stock:read', 'View stock balances and movements'),
+    ('stock:post', 'Post stock movements and reversals')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'stock:read'),
+    ('admin', 'stock:post'),
+    ('manager', 'stock:read'),
+    ('manager', 'stock:post'),
+    ('staff', 'stock:read'),
+    ('staff', 'stock:post')
+ON CONFLICT DO NOTHING;
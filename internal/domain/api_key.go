@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// APIKey authenticates a machine such as a barcode scanner or an ERP sync job.
+// Only the hash of the key is stored; the prefix identifies the key in lookups and logs.
+type APIKey struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	KeyHash    string       `json:"-"`
+	Role       UserRole     `json:"role"`
+	Scopes     []Permission `json:"scopes"` // Subset of the role permissions, all of them when empty
+	CreatedBy  string       `json:"created_by"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	LastUsedAt *time.Time   `json:"last_used_at"`
+	RevokedAt  *time.Time   `json:"revoked_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// IsUsable checks if the key is neither revoked nor expired
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// Principal returns the user the key acts as. It holds the role of the key
+// and the role permissions narrowed down to the scopes of the key.
+func (k *APIKey) Principal(rolePermissions []Permission) *User {
+	permissions := rolePermissions
+	if len(k.Scopes) > 0 {
+		permissions = []Permission{}
+		for _, permission := range rolePermissions {
+			for _, scope := range k.Scopes {
+				if permission == scope {
+					permissions = append(permissions, permission)
+					break
+				}
+			}
+		}
+	}
+
+	return &User{
+		ID:          k.ID,
+		Username:    "api-key:" + k.Prefix,
+		Fullname:    k.Name,
+		Role:        k.Role,
+		CreatedAt:   k.CreatedAt,
+		Status:      UserStatusActive,
+		Roles:       []UserRole{k.Role},
+		Permissions: permissions,
+		APIKeyID:    k.ID,
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// ApprovalLimit is the maximum amount a role or a single user may approve.
+// Empty Currency or DocumentType match any value; a nil MaxAmount means unlimited.
+type ApprovalLimit struct {
+	ID           string    `json:"id"`
+	Role         UserRole  `json:"role,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+	Currency     string    `json:"currency,omitempty"`
+	DocumentType string    `json:"document_type,omitempty"`
+	MaxAmount    *float64  `json:"max_amount"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// IsUnlimited checks if the limit allows any amount
+func (l *ApprovalLimit) IsUnlimited() bool {
+	return l.MaxAmount == nil
+}
+
+// Allows checks if amount is within the limit
+func (l *ApprovalLimit) Allows(amount float64) bool {
+	return l.MaxAmount == nil || amount <= *l.MaxAmount
+}
+
+// Specificity ranks how closely the limit matches a request; user overrides
+// always outrank role limits, then document type outranks currency.
+func (l *ApprovalLimit) Specificity() int {
+	score := 0
+	if l.UserID != "" {
+		score += 4
+	}
+	if l.DocumentType != "" {
+		score += 2
+	}
+	if l.Currency != "" {
+		score++
+	}
+	return score
+}
+
+// ApprovalDecision is the outcome of checking an amount against the applicable limit
+type ApprovalDecision struct {
+	Allowed      bool           `json:"allowed"`
+	Amount       float64        `json:"amount"`
+	Currency     string         `json:"currency"`
+	DocumentType string         `json:"document_type,omitempty"`
+	Limit        *ApprovalLimit `json:"limit"` // nil when no limit applies to the user
+}
+
+// MaxAmount returns the applicable maximum amount, nil when unlimited or when no limit applies
+func (d *ApprovalDecision) MaxAmount() *float64 {
+	if d.Limit == nil {
+		return nil
+	}
+	return d.Limit.MaxAmount
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// AuditAction names a security relevant event, written as area.action
+type AuditAction string
+
+const (
+	AuditActionLogin        AuditAction = "auth.login"
+	AuditActionMFALogin     AuditAction = "auth.mfa_login"
+	AuditActionSSOLogin     AuditAction = "auth.sso_login"
+	AuditActionRefresh      AuditAction = "auth.refresh"
+	AuditActionLogout       AuditAction = "auth.logout"
+	AuditActionRegister     AuditAction = "auth.register"
+	AuditActionAccessDenied AuditAction = "access.denied"
+
+	AuditActionImpersonationStart   AuditAction = "impersonation.start"
+	AuditActionImpersonationEnd     AuditAction = "impersonation.end"
+	AuditActionImpersonationRequest AuditAction = "impersonation.request"
+)
+
+// AuditOutcome tells whether the audited operation succeeded
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "success"
+	AuditOutcomeFailure AuditOutcome = "failure"
+	AuditOutcomeDenied  AuditOutcome = "denied"
+)
+
+// AuditEvent is an entry of the append-only security audit log.
+// The actor is empty when the caller could not be identified, e.g. on a login with an unknown username.
+type AuditEvent struct {
+	ID            string         `json:"id"`
+	ActorID       string         `json:"actor_id"`
+	ActorUsername string         `json:"actor_username"`
+	Action        AuditAction    `json:"action"`
+	Target        string         `json:"target"`
+	Outcome       AuditOutcome   `json:"outcome"`
+	IPAddress     string         `json:"ip_address"`
+	UserAgent     string         `json:"user_agent"`
+	Metadata      map[string]any `json:"metadata"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
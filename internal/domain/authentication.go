@@ -2,9 +2,22 @@ package domain
 
 import "time"
 
-// Authentication represents refresh token stored in database
+// Authentication represents refresh token stored in database.
+// Only the SHA-256 hash of the token is kept so a database leak does not leak sessions.
 type Authentication struct {
-	Token     string    `json:"token"`
-	UserID    string    `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
+	TokenHash       string     `json:"-"`
+	UserID          string     `json:"user_id"`
+	FamilyID        string     `json:"family_id"` // Shared by every token rotated from the same login
+	ParentTokenHash string     `json:"-"`         // Token this one was rotated from, empty for a fresh login
+	UserAgent       string     `json:"user_agent"`
+	IPAddress       string     `json:"ip_address"`
+	RevokedAt       *time.Time `json:"revoked_at"` // Set once the token has been rotated
+	ExpiresAt       time.Time  `json:"expires_at"`
+	LastUsedAt      time.Time  `json:"last_used_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// IsRevoked checks if the token has already been rotated or revoked
+func (a *Authentication) IsRevoked() bool {
+	return a.RevokedAt != nil
 }
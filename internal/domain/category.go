@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Category is a node of the product category tree, e.g. Electronics > Cables > HDMI
+type Category struct {
+	ID          string `json:"id"`
+	ParentID    string `json:"parent_id,omitempty"` // Empty for root categories
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Path holds the names from the root down to the category itself
+	Path      []string  `json:"path"`
+	Depth     int       `json:"depth"` // Zero for root categories
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Only filled in when the categories are returned as a tree
+	Children []*Category `json:"children,omitempty"`
+}
+
+// BuildCategoryTree nests categories under their parents and returns the
+// top-level ones. Categories whose parent is not listed become top-level,
+// so a subtree keeps its root. The order of siblings is kept.
+func BuildCategoryTree(categories []*Category) []*Category {
+	byID := make(map[string]*Category, len(categories))
+	for _, category := range categories {
+		category.Children = []*Category{}
+		byID[category.ID] = category
+	}
+
+	roots := []*Category{}
+	for _, category := range categories {
+		if parent, ok := byID[category.ParentID]; ok {
+			parent.Children = append(parent.Children, category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+
+	return roots
+}
@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DecimalPlaces is the number of fraction digits a Decimal holds, as NUMERIC(20, 6) columns do
+	DecimalPlaces = 6
+	// DecimalIntegerDigits is the number of integer digits NUMERIC(20, 6) columns hold
+	DecimalIntegerDigits = 20 - DecimalPlaces
+)
+
+var decimalUnit = pow10(DecimalPlaces)
+
+// Decimal is an exact fixed-point number with DecimalPlaces fraction digits, used for
+// quantities and conversion factors. The zero value is 0. It is marshalled to JSON
+// as a string so clients do not lose precision, and read from strings or numbers.
+type Decimal struct {
+	scaled *big.Int // Value times 10^DecimalPlaces
+}
+
+// NewDecimal returns the decimal value of an integer
+func NewDecimal(value int64) Decimal {
+	scaled := big.NewInt(value)
+	return Decimal{scaled: scaled.Mul(scaled, decimalUnit)}
+}
+
+// ParseDecimal parses a plain decimal number such as "12", "-0.5" or "1.250".
+// Exponents are not accepted, nor more than DecimalPlaces fraction digits or
+// DecimalIntegerDigits integer digits.
+func ParseDecimal(value string) (Decimal, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	unsigned := strings.TrimLeft(value, "+-")
+	if len(value)-len(unsigned) > 1 {
+		return Decimal{}, ErrDecimalInvalid
+	}
+
+	integer, fraction, _ := strings.Cut(unsigned, ".")
+	if integer == "" && fraction == "" || !isDigits(integer) || !isDigits(fraction) {
+		return Decimal{}, ErrDecimalInvalid
+	}
+	if len(fraction) > DecimalPlaces {
+		return Decimal{}, ErrDecimalPrecision
+	}
+	if len(strings.TrimLeft(integer, "0")) > DecimalIntegerDigits {
+		return Decimal{}, ErrDecimalRange
+	}
+
+	digits := integer + fraction + strings.Repeat("0", DecimalPlaces-len(fraction))
+	scaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, ErrDecimalInvalid
+	}
+	if negative {
+		scaled.Neg(scaled)
+	}
+
+	return Decimal{scaled: scaled}, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// int returns the scaled value, never nil
+func (d Decimal) int() *big.Int {
+	if d.scaled == nil {
+		return new(big.Int)
+	}
+	return d.scaled
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{scaled: new(big.Int).Add(d.int(), other.int())}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{scaled: new(big.Int).Sub(d.int(), other.int())}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{scaled: new(big.Int).Neg(d.int())}
+}
+
+// Cmp returns -1, 0 or +1 when d is less than, equal to or greater than other
+func (d Decimal) Cmp(other Decimal) int {
+	return d.int().Cmp(other.int())
+}
+
+// Sign returns -1, 0 or +1 for negative, zero and positive values
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero checks if d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Round rounds d to places fraction digits and reports whether the value changed.
+// RoundingReject truncates; callers refuse the value when it was inexact.
+func (d Decimal) Round(places int, rule RoundingRule) (Decimal, bool) {
+	if places >= DecimalPlaces {
+		return d, false
+	}
+
+	rounded, inexact := divRound(d.int(), pow10(DecimalPlaces-places), rule)
+	return Decimal{scaled: rounded.Mul(rounded, pow10(DecimalPlaces-places))}, inexact
+}
+
+// MulRound returns d * other rounded to places fraction digits and reports whether rounding changed the exact product
+func (d Decimal) MulRound(other Decimal, places int, rule RoundingRule) (Decimal, bool) {
+	places = min(places, DecimalPlaces)
+
+	// The exact product has twice DecimalPlaces fraction digits
+	product := new(big.Int).Mul(d.int(), other.int())
+	rounded, inexact := divRound(product, pow10(2*DecimalPlaces-places), rule)
+	return Decimal{scaled: rounded.Mul(rounded, pow10(DecimalPlaces-places))}, inexact
+}
+
+// divRound divides value by divisor, rounding the quotient according to rule
+func divRound(value, divisor *big.Int, rule RoundingRule) (*big.Int, bool) {
+	quotient, remainder := new(big.Int).QuoRem(value, divisor, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient, false
+	}
+
+	away := big.NewInt(int64(value.Sign()))
+	switch rule {
+	case RoundingUp:
+		quotient.Add(quotient, away)
+	case RoundingHalfUp:
+		twice := new(big.Int).Abs(remainder)
+		if twice.Lsh(twice, 1).Cmp(divisor) >= 0 {
+			quotient.Add(quotient, away)
+		}
+	}
+
+	return quotient, true
+}
+
+// String formats d without trailing fraction zeros, e.g. "144" or "0.5"
+func (d Decimal) String() string {
+	scaled := d.int()
+
+	integer, fraction := new(big.Int).QuoRem(new(big.Int).Abs(scaled), decimalUnit, new(big.Int))
+	result := integer.String()
+	if fraction.Sign() != 0 {
+		digits := fmt.Sprintf("%0*s", DecimalPlaces, fraction.String())
+		result += "." + strings.TrimRight(digits, "0")
+	}
+	if scaled.Sign() < 0 {
+		result = "-" + result
+	}
+
+	return result
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		*d = Decimal{}
+		return nil
+	}
+
+	// Numbers are parsed from their literal, never through float64
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// Scan reads NUMERIC columns, which the driver returns as text
+func (d *Decimal) Scan(src any) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
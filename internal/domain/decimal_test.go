@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustParseDecimal(t *testing.T, value string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(value)
+	if err != nil {
+		t.Fatalf("ParseDecimal(%q) error = %v", value, err)
+	}
+	return d
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{input: "12", want: "12"},
+		{input: "-0.5", want: "-0.5"},
+		{input: "1.250", want: "1.25"},
+		{input: "-.5", want: "-0.5"},
+		{input: "+.5", want: "0.5"},
+		{input: ".5", want: "0.5"},
+		{input: "1.", want: "1"},
+		{input: "  7 ", want: "7"},
+		{input: "-0", want: "0"},
+		{input: "0.000001", want: "0.000001"},
+		{input: "99999999999999.999999", want: "99999999999999.999999"},
+		{input: "000099999999999999", want: "99999999999999"},
+		{input: "0.0000001", err: ErrDecimalPrecision},
+		{input: "1.1234567", err: ErrDecimalPrecision},
+		{input: "100000000000000", err: ErrDecimalRange},
+		{input: "-100000000000000.5", err: ErrDecimalRange},
+		{input: "+-1", err: ErrDecimalInvalid},
+		{input: "--1", err: ErrDecimalInvalid},
+		{input: "", err: ErrDecimalInvalid},
+		{input: "-", err: ErrDecimalInvalid},
+		{input: ".", err: ErrDecimalInvalid},
+		{input: "1e3", err: ErrDecimalInvalid},
+		{input: "1.2.3", err: ErrDecimalInvalid},
+		{input: "1,5", err: ErrDecimalInvalid},
+		{input: "0x10", err: ErrDecimalInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDecimal(tt.input)
+			if err != tt.err {
+				t.Fatalf("ParseDecimal(%q) error = %v, want %v", tt.input, err, tt.err)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseDecimal(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalMulRound(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		factor      string
+		places      int
+		rule        RoundingRule
+		want        string
+		wantInexact bool
+	}{
+		{name: "exact", value: "-2.5", factor: "12", places: 0, rule: RoundingReject, want: "-30"},
+		{name: "half up rounds halves away from zero", value: "-0.25", factor: "1", places: 1, rule: RoundingHalfUp, want: "-0.3", wantInexact: true},
+		{name: "half up positive half", value: "0.25", factor: "1", places: 1, rule: RoundingHalfUp, want: "0.3", wantInexact: true},
+		{name: "half up below half", value: "-0.24", factor: "1", places: 1, rule: RoundingHalfUp, want: "-0.2", wantInexact: true},
+		{name: "up rounds away from zero", value: "-0.21", factor: "1", places: 1, rule: RoundingUp, want: "-0.3", wantInexact: true},
+		{name: "up positive", value: "0.21", factor: "1", places: 1, rule: RoundingUp, want: "0.3", wantInexact: true},
+		{name: "down rounds towards zero", value: "-0.29", factor: "1", places: 1, rule: RoundingDown, want: "-0.2", wantInexact: true},
+		{name: "down positive", value: "0.29", factor: "1", places: 1, rule: RoundingDown, want: "0.2", wantInexact: true},
+		{name: "reject truncates and reports inexact", value: "-0.29", factor: "1", places: 1, rule: RoundingReject, want: "-0.2", wantInexact: true},
+		{name: "negative factor", value: "1.5", factor: "-0.333333", places: 2, rule: RoundingHalfUp, want: "-0.5", wantInexact: true},
+		{name: "places beyond DecimalPlaces", value: "0.000001", factor: "0.5", places: 10, rule: RoundingHalfUp, want: "0.000001", wantInexact: true},
+		{name: "places beyond DecimalPlaces down", value: "-0.000001", factor: "0.5", places: 10, rule: RoundingDown, want: "0", wantInexact: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := mustParseDecimal(t, tt.value)
+			factor := mustParseDecimal(t, tt.factor)
+
+			got, inexact := value.MulRound(factor, tt.places, tt.rule)
+			if got.String() != tt.want || inexact != tt.wantInexact {
+				t.Errorf("%s.MulRound(%s, %d, %s) = (%s, %v), want (%s, %v)",
+					tt.value, tt.factor, tt.places, tt.rule, got, inexact, tt.want, tt.wantInexact)
+			}
+		})
+	}
+}
+
+func TestDecimalStringRoundTrip(t *testing.T) {
+	for _, value := range []string{"0", "1", "-1", "0.5", "-0.000001", "144", "12.34", "99999999999999.999999"} {
+		t.Run(value, func(t *testing.T) {
+			d := mustParseDecimal(t, value)
+			if d.String() != value {
+				t.Fatalf("ParseDecimal(%q).String() = %q", value, d.String())
+			}
+			if again := mustParseDecimal(t, d.String()); again.Cmp(d) != 0 {
+				t.Errorf("round trip of %q = %s", value, again)
+			}
+		})
+	}
+
+	var zero Decimal
+	if zero.String() != "0" {
+		t.Errorf("zero value String() = %q, want \"0\"", zero.String())
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type payload struct {
+		Quantity Decimal `json:"quantity"`
+	}
+
+	tests := []struct {
+		name string
+		json string
+		want string
+		err  error
+	}{
+		{name: "string", json: `{"quantity":"-2.5"}`, want: "-2.5"},
+		{name: "number", json: `{"quantity":1.25}`, want: "1.25"},
+		{name: "large number keeps precision", json: `{"quantity":12345678901234.123456}`, want: "12345678901234.123456"},
+		{name: "null", json: `{"quantity":null}`, want: "0"},
+		{name: "exponent", json: `{"quantity":1e3}`, err: ErrDecimalInvalid},
+		{name: "too many places", json: `{"quantity":"0.1234567"}`, err: ErrDecimalPrecision},
+		{name: "out of range", json: `{"quantity":"100000000000000"}`, err: ErrDecimalRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p payload
+			err := json.Unmarshal([]byte(tt.json), &p)
+			if err != tt.err {
+				t.Fatalf("Unmarshal(%s) error = %v, want %v", tt.json, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if p.Quantity.String() != tt.want {
+				t.Fatalf("Unmarshal(%s) = %s, want %s", tt.json, p.Quantity, tt.want)
+			}
+
+			data, err := json.Marshal(p)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if want := `{"quantity":"` + tt.want + `"}`; string(data) != want {
+				t.Errorf("Marshal() = %s, want %s", data, want)
+			}
+		})
+	}
+}
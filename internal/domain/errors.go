@@ -8,13 +8,104 @@ var (
 	ErrUsernameAlreadyUsed = errors.New("username already used")
 	ErrInvalidCredentials  = errors.New("invalid credentials")
 	ErrUserIDInvalid       = errors.New("user id is invalid")
+	ErrPasswordMismatch    = errors.New("current password is incorrect")
+	ErrAccountLocked       = errors.New("account is temporarily locked")
+	ErrCannotManageSelf    = errors.New("cannot remove, demote or deactivate your own account")
+	ErrAccountSuspended    = errors.New("account is suspended")
+	ErrAccountDeactivated  = errors.New("account is deactivated")
+	ErrInvalidStatusChange = errors.New("account status cannot be changed to the requested status")
+	ErrUserInUse           = errors.New("user is referenced by stock movements")
+
+	// Impersonation errors
+	ErrCannotImpersonate = errors.New("user cannot be impersonated")
+	ErrNotImpersonating  = errors.New("not impersonating a user")
+
+	// Two-factor authentication errors
+	ErrMFANotEnrolled      = errors.New("two-factor authentication is not enrolled")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFARequiredByPolicy = errors.New("two-factor authentication is required for this role")
+	ErrInvalidMFACode      = errors.New("invalid two-factor authentication code")
+
+	// Role errors
+	ErrRoleNotFound       = errors.New("role not found")
+	ErrRoleAlreadyExists  = errors.New("role already exists")
+	ErrRoleInUse          = errors.New("role is assigned to users")
+	ErrPermissionNotFound = errors.New("permission not found")
+	ErrUserRolesRequired  = errors.New("at least one role is required")
+
+	// Invitation errors
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationInvalid  = errors.New("invitation is invalid, expired or already used")
+	ErrRegistrationClosed = errors.New("self-registration is disabled")
+
+	// API key errors
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrAPIKeyInvalid      = errors.New("api key is invalid, expired or revoked")
+	ErrAPIKeyScopeInvalid = errors.New("api key scope is not granted by its role")
+
+	// Single sign-on errors
+	ErrIdentityNotFound    = errors.New("external identity not found")
+	ErrOIDCStateInvalid    = errors.New("single sign-on state is invalid or expired")
+	ErrOIDCLoginFailed     = errors.New("identity provider login failed")
+	ErrOIDCUsernameMissing = errors.New("identity provider did not return a usable username")
+	ErrOIDCMFAMissing      = errors.New("identity provider did not confirm a second factor")
+
+	// Product errors
+	ErrProductNotFound    = errors.New("product not found")
+	ErrSKUAlreadyUsed     = errors.New("sku already used")
+	ErrBarcodeAlreadyUsed = errors.New("barcode already used")
+	ErrProductInUse       = errors.New("product has stock movements")
+
+	// Category errors
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryAlreadyExists = errors.New("category with the same name already exists under the parent")
+	ErrCategoryCycle         = errors.New("category cannot be moved below itself")
+	ErrCategoryInUse         = errors.New("category holds subcategories or products")
+
+	// Unit of measure errors
+	ErrUoMNotFound         = errors.New("unit of measure not found")
+	ErrUoMAlreadyExists    = errors.New("unit of measure already exists")
+	ErrUoMInUse            = errors.New("unit of measure is used by products")
+	ErrProductUnitNotFound = errors.New("unit of measure is not defined for the product")
+	ErrDecimalInvalid      = errors.New("invalid decimal number")
+	ErrDecimalPrecision    = errors.New("too many decimal places")
+	ErrDecimalRange        = errors.New("decimal number out of range")
+
+	// Warehouse errors
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	ErrWarehouseCodeUsed = errors.New("warehouse code already used")
+	ErrWarehouseInUse    = errors.New("warehouse still holds locations")
+	ErrLocationNotFound  = errors.New("location not found")
+	ErrLocationCodeUsed  = errors.New("location code already used in the warehouse")
+	ErrLocationInUse     = errors.New("location still holds other locations or stock movements")
+
+	// Stock ledger errors
+	ErrPostingNotFound       = errors.New("stock posting not found")
+	ErrPostingReversed       = errors.New("stock posting is already reversed")
+	ErrCannotReverseReversal = errors.New("a reversal cannot be reversed")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrQuantityOutOfRange    = errors.New("quantity out of range")
+	ErrWarehouseNotAssigned  = errors.New("user is not assigned to the warehouse")
+
+	// Approval limit errors
+	ErrApprovalLimitNotFound = errors.New("approval limit not found")
+	ErrApprovalLimitExists   = errors.New("approval limit already exists")
+	ErrApprovalLimitInvalid  = errors.New("approval limit must target either a role or a user")
+	ErrApprovalNotAllowed    = errors.New("amount exceeds approval limit")
+
+	// Login throttling errors
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+	ErrLoginAttemptNotFound = errors.New("login attempt not found")
 
 	// Authentication errors
 	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected")
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrTokenExpired         = errors.New("token expired")
 	ErrUnauthorized         = errors.New("unauthorized")
 	ErrForbidden            = errors.New("forbidden - insufficient permissions")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrResetTokenInvalid    = errors.New("reset token is invalid or expired")
 
 	// General errors
 	ErrInternalServer = errors.New("internal server error")
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ExternalIdentity links an account of an external identity provider to a user.
+// The provider account is identified by the issuer and subject of its ID tokens.
+type ExternalIdentity struct {
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	UserID      string    `json:"user_id"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// OIDCLoginState is a pending single sign-on login, kept between the redirect
+// to the identity provider and its callback. Only the hash of the state is stored.
+type OIDCLoginState struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
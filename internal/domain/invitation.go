@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Invitation lets a new user register with a role chosen by an admin.
+// Only the hash of the single-use token is stored.
+type Invitation struct {
+	ID        string     `json:"id"`
+	Role      UserRole   `json:"role"`
+	TokenHash string     `json:"-"`
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	UsedBy    string     `json:"used_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsPending checks if the invitation can still be redeemed
+func (i *Invitation) IsPending(now time.Time) bool {
+	return i.UsedAt == nil && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// LoginAttempt tracks failed logins for a username or an IP address
+type LoginAttempt struct {
+	Key           string     `json:"key"`
+	FailedCount   int        `json:"failed_count"`
+	LockoutCount  int        `json:"lockout_count"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+}
+
+// IsLocked checks if the key is locked at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && a.LockedUntil.After(now)
+}
+
+// LockoutError is returned when a login is refused because of too many failed attempts.
+// It wraps ErrAccountLocked or ErrTooManyLoginAttempts.
+type LockoutError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LockoutError) Unwrap() error {
+	return e.Err
+}
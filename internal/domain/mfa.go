@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// UserMFA represents the TOTP second factor of a user
+type UserMFA struct {
+	UserID       string     `json:"user_id"`
+	Secret       string     `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"` // Nil while the enrollment is not confirmed
+	LastUsedStep int64      `json:"-"`          // Last accepted TOTP time step, used to reject replays
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsEnabled checks if the enrollment has been confirmed with a valid code
+func (m *UserMFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
+
+// MFAEnrollment is returned when a user starts enrolling an authenticator app
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// PasswordHistoryEntry is a hash of a password a user had, kept to prevent reuse
+type PasswordHistoryEntry struct {
+	UserID       string    `json:"user_id"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
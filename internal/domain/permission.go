@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Permission represents a single action a user may perform, written as resource:action
+type Permission string
+
+const (
+	PermissionUserRead        Permission = "user:read"
+	PermissionUserManage      Permission = "user:manage"
+	PermissionUserImpersonate Permission = "user:impersonate"
+	PermissionRoleManage      Permission = "role:manage"
+	PermissionProductRead     Permission = "product:read"
+	PermissionProductWrite    Permission = "product:write"
+	PermissionPOCreate        Permission = "po:create"
+	PermissionPOApprove       Permission = "po:approve"
+	PermissionApprovalManage  Permission = "approval:manage"
+	PermissionAPIKeyManage    Permission = "api_key:manage"
+	PermissionAuditRead       Permission = "audit:read"
+	PermissionWarehouseManage Permission = "warehouse:manage"
+	PermissionStockRead       Permission = "stock:read"
+	PermissionStockPost       Permission = "stock:post"
+)
+
+// PermissionInfo describes a permission known to the system
+type PermissionInfo struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+}
+
+// Role represents a named set of permissions stored in the database
+type Role struct {
+	Name        UserRole     `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
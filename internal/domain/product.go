@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Product represents an item of the product catalog
+type Product struct {
+	ID            string    `json:"id"`
+	SKU           string    `json:"sku"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	CategoryID    string    `json:"category_id,omitempty"`
+	UnitOfMeasure string    `json:"unit_of_measure"`
+	Barcode       string    `json:"barcode,omitempty"`
+	Cost          float64   `json:"cost"`
+	Active        bool      `json:"active"` // Inactive products are kept for history but no longer used
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// ClientInfo describes the client a request originates from
+type ClientInfo struct {
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+}
+
+// Session represents a login on a single device.
+// A session is the family of refresh tokens rotated from one login,
+// so its ID is the family ID of those tokens.
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// MovementType tells why stock changed
+type MovementType string
+
+const (
+	MovementReceipt    MovementType = "receipt"
+	MovementIssue      MovementType = "issue"
+	MovementAdjustment MovementType = "adjustment" // Signed correction, e.g. after a stock count
+	MovementTransfer   MovementType = "transfer"   // Posted as a pair, out of one location and into another
+)
+
+// IsValid checks if the movement type is known
+func (t MovementType) IsValid() bool {
+	switch t {
+	case MovementReceipt, MovementIssue, MovementAdjustment, MovementTransfer:
+		return true
+	}
+	return false
+}
+
+// StockMovement is an entry of the append-only stock ledger. Movements are
+// never changed; a mistake is corrected by a movement reversing it.
+type StockMovement struct {
+	ID string `json:"id"`
+	// Sequence orders the ledger; it increases with every movement
+	Sequence int64 `json:"sequence"`
+	// PostingID groups the movements posted together
+	PostingID   string `json:"posting_id"`
+	ProductID   string `json:"product_id"`
+	LocationID  string `json:"location_id"`
+	WarehouseID string `json:"warehouse_id"`
+	// Quantity is the signed change in the base unit UoM of the product
+	Quantity Decimal `json:"quantity"`
+	UoM      string  `json:"uom"`
+	// EnteredQuantity and EnteredUoM are the quantity as posted, before conversion
+	EnteredQuantity Decimal `json:"entered_quantity"`
+	EnteredUoM      string  `json:"entered_uom"`
+	// BalanceAfter is the on-hand quantity of the product at the location after the movement
+	BalanceAfter  Decimal      `json:"balance_after"`
+	Type          MovementType `json:"type"`
+	ReferenceType string       `json:"reference_type"`
+	ReferenceID   string       `json:"reference_id"`
+	Note          string       `json:"note"`
+	// ReversesID is the movement this one reverses, empty for other movements
+	ReversesID string `json:"reverses_id,omitempty"`
+	// UserID and APIKeyID record who posted the movement; exactly one is set
+	UserID    string    `json:"user_id,omitempty"`
+	APIKeyID  string    `json:"api_key_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StockPosting is a group of movements posted in one transaction
+type StockPosting struct {
+	ID        string           `json:"id"`
+	Movements []*StockMovement `json:"movements"`
+}
+
+// StockBalance is the on-hand quantity of a product at a location, projected from the ledger
+type StockBalance struct {
+	ProductID    string    `json:"product_id"`
+	SKU          string    `json:"sku"`
+	LocationID   string    `json:"location_id"`
+	LocationCode string    `json:"location_code"`
+	WarehouseID  string    `json:"warehouse_id"`
+	Quantity     Decimal   `json:"quantity"`
+	UoM          string    `json:"uom"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
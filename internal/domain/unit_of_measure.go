@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// RoundingRule decides what happens to quantities with more fraction digits than a unit allows
+type RoundingRule string
+
+const (
+	RoundingReject RoundingRule = "reject"  // Refuse the quantity with a validation error
+	RoundingHalfUp RoundingRule = "half_up" // Round to the nearest value, halves away from zero
+	RoundingUp     RoundingRule = "up"      // Round away from zero
+	RoundingDown   RoundingRule = "down"    // Round towards zero
+)
+
+// IsValid checks if the rounding rule is known
+func (r RoundingRule) IsValid() bool {
+	switch r {
+	case RoundingReject, RoundingHalfUp, RoundingUp, RoundingDown:
+		return true
+	}
+	return false
+}
+
+// UnitOfMeasure is an entry of the units of measure master, e.g. PCS, BOX or KG
+type UnitOfMeasure struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// Decimals is the number of fraction digits quantities in this unit may have
+	Decimals int `json:"decimals"`
+	// Rounding applies when a quantity converted into this unit has more digits than Decimals
+	Rounding  RoundingRule `json:"rounding"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// ProductUnit is a unit a product is handled in besides its base unit,
+// e.g. BOX with factor 12 when the base unit is PCS
+type ProductUnit struct {
+	ProductID string `json:"product_id"`
+	UoM       string `json:"uom"`
+	// Factor is the number of base units in one of this unit
+	Factor    Decimal   `json:"factor"`
+	IsBase    bool      `json:"is_base"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NormalizedQuantity is a quantity converted to the base unit of its product
+type NormalizedQuantity struct {
+	ProductID    string  `json:"product_id"`
+	Quantity     Decimal `json:"quantity"`
+	UoM          string  `json:"uom"`
+	Factor       Decimal `json:"factor"`
+	BaseQuantity Decimal `json:"base_quantity"`
+	BaseUoM      string  `json:"base_uom"`
+	// Rounded reports that BaseQuantity was rounded according to the rule of the base unit
+	Rounded bool `json:"rounded"`
+}
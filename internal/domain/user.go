@@ -11,26 +11,60 @@ const (
 	RoleStaff   UserRole = "staff"
 )
 
+// UserStatus represents the lifecycle state of an account
+type UserStatus string
+
+const (
+	UserStatusActive      UserStatus = "active"
+	UserStatusSuspended   UserStatus = "suspended"   // Temporarily blocked, e.g. during an investigation
+	UserStatusDeactivated UserStatus = "deactivated" // Left the company, kept for history
+)
+
+// MaxUsernameLength is the most characters a username holds, as the users.username column does
+const MaxUsernameLength = 50
+
 // User represents user entity
 type User struct {
 	ID        string    `json:"id"`
 	Username  string    `json:"username"`
 	Password  string    `json:"-"` // Never expose password in JSON
 	Fullname  string    `json:"fullname"`
-	Role      UserRole  `json:"role"`
+	Role      UserRole  `json:"role"` // Primary role, always part of Roles
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	Status          UserStatus `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+
+	// Loaded by the auth middleware for authorization checks
+	Roles       []UserRole   `json:"roles,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty"`
+
+	// APIKeyID is set when the user is the principal of an API key, whose ID it shares
+	APIKeyID string `json:"-"`
 }
 
-// CanApprove checks if user can approve a purchase based on amount
-func (u *User) CanApprove(amount float64) bool {
-	// Admin and Manager can approve any amount
-	if u.Role == RoleAdmin || u.Role == RoleManager {
-		return true
+// CheckActive returns the error explaining why an inactive account cannot be used
+func (u *User) CheckActive() error {
+	switch u.Status {
+	case UserStatusSuspended:
+		return ErrAccountSuspended
+	case UserStatusDeactivated:
+		return ErrAccountDeactivated
 	}
-	// Staff can only approve if amount is less than or equal to 10,000,000
-	if u.Role == RoleStaff && amount <= 10000000 {
-		return true
+	return nil
+}
+
+// CanTransitionTo checks if the account may move to status
+func (u *User) CanTransitionTo(status UserStatus) bool {
+	switch status {
+	case UserStatusActive:
+		return u.Status == UserStatusSuspended || u.Status == UserStatusDeactivated
+	case UserStatusSuspended:
+		return u.Status == UserStatusActive
+	case UserStatusDeactivated:
+		return u.Status == UserStatusActive || u.Status == UserStatusSuspended
 	}
 	return false
 }
@@ -39,3 +73,26 @@ func (u *User) CanApprove(amount float64) bool {
 func (u *User) HasFullAccess() bool {
 	return u.Role == RoleAdmin || u.Role == RoleManager
 }
+
+// HasRole checks if user holds the given role
+func (u *User) HasRole(role UserRole) bool {
+	if u.Role == role {
+		return true
+	}
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission checks if any role of the user grants the given permission
+func (u *User) HasPermission(permission Permission) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
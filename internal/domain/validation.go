@@ -0,0 +1,24 @@
+package domain
+
+import "strings"
+
+// FieldError describes why the value of a request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when request fields break one or more rules.
+// Every broken rule is reported, not only the first one.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	codes := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		codes[i] = fieldErr.Field + ": " + fieldErr.Code
+	}
+	return "validation failed (" + strings.Join(codes, ", ") + ")"
+}
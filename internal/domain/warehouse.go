@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// Warehouse is a site stock is kept in; users operate the warehouses they are assigned to
+type Warehouse struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LocationLevel is the place of a location in the warehouse hierarchy
+type LocationLevel string
+
+const (
+	LocationZone  LocationLevel = "zone"
+	LocationAisle LocationLevel = "aisle" // Always inside a zone
+	LocationBin   LocationLevel = "bin"   // Always inside an aisle
+)
+
+// ParentLevel returns the level of the parent a location of this level needs, empty for zones
+func (l LocationLevel) ParentLevel() LocationLevel {
+	switch l {
+	case LocationAisle:
+		return LocationZone
+	case LocationBin:
+		return LocationAisle
+	}
+	return ""
+}
+
+// IsValid checks if the location level is known
+func (l LocationLevel) IsValid() bool {
+	return l == LocationZone || l == LocationAisle || l == LocationBin
+}
+
+// LocationType tells what a location is used for
+type LocationType string
+
+const (
+	LocationReceiving  LocationType = "receiving"
+	LocationStorage    LocationType = "storage"
+	LocationPicking    LocationType = "picking"
+	LocationQuarantine LocationType = "quarantine" // Stock that must not be picked, e.g. damaged goods
+)
+
+// IsValid checks if the location type is known
+func (t LocationType) IsValid() bool {
+	switch t {
+	case LocationReceiving, LocationStorage, LocationPicking, LocationQuarantine:
+		return true
+	}
+	return false
+}
+
+// Location is a zone, aisle or bin of a warehouse
+type Location struct {
+	ID          string        `json:"id"`
+	WarehouseID string        `json:"warehouse_id"`
+	ParentID    string        `json:"parent_id,omitempty"` // Empty for zones
+	Code        string        `json:"code"`                // Unique within the warehouse
+	Name        string        `json:"name"`
+	Level       LocationLevel `json:"level"`
+	Type        LocationType  `json:"type"`
+	// Capacity is the quantity the location holds, unlimited when nil
+	Capacity  *Decimal  `json:"capacity"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHandler handles API key HTTP requests
+type APIKeyHandler struct {
+	apiKeyUseCase usecase.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyUseCase usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// Create handles creating an API key
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var req usecase.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.Name == "" || req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Name and role are required",
+		})
+	}
+
+	key, err := h.apiKeyUseCase.Create(c.Context(), &req, user.ID)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   key,
+	})
+}
+
+// List handles listing API keys
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	keys, err := h.apiKeyUseCase.List(c.Context())
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"api_keys": keys,
+		},
+	})
+}
+
+// Revoke handles revoking an API key
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	err := h.apiKeyUseCase.Revoke(c.Context(), c.Params("id"))
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "API key revoked",
+	})
+}
+
+// apiKeyError maps API key errors to responses
+func apiKeyError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrAPIKeyNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "API key not found or already revoked",
+		})
+	case domain.ErrRoleNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	case domain.ErrAPIKeyScopeInvalid, domain.ErrPermissionNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Scopes must be permissions of the key role",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
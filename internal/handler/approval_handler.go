@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ApprovalHandler handles approval limit HTTP requests
+type ApprovalHandler struct {
+	approvalUseCase usecase.ApprovalUseCase
+}
+
+// NewApprovalHandler creates a new approval handler
+func NewApprovalHandler(approvalUseCase usecase.ApprovalUseCase) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalUseCase: approvalUseCase,
+	}
+}
+
+// CheckApproval handles checking whether the current user may approve an amount
+func (h *ApprovalHandler) CheckApproval(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var req usecase.ApprovalRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	decision, err := h.approvalUseCase.Decide(c.Context(), user, &req)
+	if err != nil {
+		return approvalLimitError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   decision,
+	})
+}
+
+// ListLimits handles listing approval limits, optionally filtered by role or user
+func (h *ApprovalHandler) ListLimits(c *fiber.Ctx) error {
+	limits, err := h.approvalUseCase.ListLimits(c.Context(), repository.ApprovalLimitFilter{
+		Role:   domain.UserRole(c.Query("role")),
+		UserID: c.Query("user_id"),
+	})
+	if err != nil {
+		return approvalLimitError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"limits": limits,
+		},
+	})
+}
+
+// GetLimit handles getting one approval limit
+func (h *ApprovalHandler) GetLimit(c *fiber.Ctx) error {
+	limit, err := h.approvalUseCase.GetLimit(c.Context(), c.Params("id"))
+	if err != nil {
+		return approvalLimitError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   limit,
+	})
+}
+
+// CreateLimit handles creating an approval limit for a role or a user
+func (h *ApprovalHandler) CreateLimit(c *fiber.Ctx) error {
+	var req usecase.ApprovalLimitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	limit, err := h.approvalUseCase.CreateLimit(c.Context(), &req)
+	if err != nil {
+		return approvalLimitError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   limit,
+	})
+}
+
+// UpdateLimit handles changing the amount of an approval limit
+func (h *ApprovalHandler) UpdateLimit(c *fiber.Ctx) error {
+	var req usecase.UpdateApprovalLimitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	limit, err := h.approvalUseCase.UpdateLimit(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return approvalLimitError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   limit,
+	})
+}
+
+// DeleteLimit handles deleting an approval limit
+func (h *ApprovalHandler) DeleteLimit(c *fiber.Ctx) error {
+	err := h.approvalUseCase.DeleteLimit(c.Context(), c.Params("id"))
+	if err != nil {
+		return approvalLimitError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Approval limit deleted",
+	})
+}
+
+// approvalLimitError maps approval limit errors to responses
+func approvalLimitError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrApprovalLimitNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Approval limit not found",
+		})
+	case domain.ErrApprovalLimitExists:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "An approval limit already exists for this role or user, currency and document type",
+		})
+	case domain.ErrApprovalLimitInvalid:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Set either role or user_id",
+		})
+	case domain.ErrRoleNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	case domain.ErrUserNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	case domain.ErrBadRequest:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Currency must be a 3 letter code and max_amount must not be negative",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
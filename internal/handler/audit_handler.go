@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	auditUseCase usecase.AuditUseCase
+}
+
+// NewAuditHandler creates a new audit log handler
+func NewAuditHandler(auditUseCase usecase.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+	}
+}
+
+// List handles listing audit events with ?actor=, ?action=, ?outcome=, ?from=, ?to=, ?page= and ?page_size=
+func (h *AuditHandler) List(c *fiber.Ctx) error {
+	req, err := auditQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": err.Error(),
+		})
+	}
+
+	result, err := h.auditUseCase.List(c.Context(), req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// Export handles downloading every audit event matching the filters as CSV
+func (h *AuditHandler) Export(c *fiber.Ctx) error {
+	req, err := auditQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": err.Error(),
+		})
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{
+		"id", "created_at", "actor_id", "actor_username", "action", "target",
+		"outcome", "ip_address", "user_agent", "metadata",
+	})
+
+	err = h.auditUseCase.Export(c.Context(), req, func(event *domain.AuditEvent) error {
+		metadata, err := json.Marshal(event.Metadata)
+		if err != nil {
+			return err
+		}
+
+		return writer.Write([]string{
+			event.ID,
+			event.CreatedAt.UTC().Format(time.RFC3339),
+			csvValue(event.ActorID),
+			csvValue(event.ActorUsername),
+			string(event.Action),
+			csvValue(event.Target),
+			string(event.Outcome),
+			csvValue(event.IPAddress),
+			csvValue(event.UserAgent),
+			csvValue(string(metadata)),
+		})
+	})
+	if err == nil {
+		writer.Flush()
+		err = writer.Error()
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	filename := fmt.Sprintf("audit-events-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+// auditQuery reads the audit log filters from the query string.
+// Dates without time cover the whole day.
+func auditQuery(c *fiber.Ctx) (*usecase.ListAuditEventsRequest, error) {
+	from, err := parseAuditTime(c.Query("from"), false)
+	if err != nil {
+		return nil, errors.New("Invalid from, use YYYY-MM-DD or RFC 3339")
+	}
+
+	to, err := parseAuditTime(c.Query("to"), true)
+	if err != nil {
+		return nil, errors.New("Invalid to, use YYYY-MM-DD or RFC 3339")
+	}
+
+	return &usecase.ListAuditEventsRequest{
+		Actor:    c.Query("actor"),
+		Action:   domain.AuditAction(c.Query("action")),
+		Outcome:  domain.AuditOutcome(c.Query("outcome")),
+		From:     from,
+		To:       to,
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size"),
+	}, nil
+}
+
+// parseAuditTime parses an RFC 3339 time or a date, the end of the date when end is set
+func parseAuditTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t, nil
+}
+
+// csvValue neutralizes values a spreadsheet would run as formula,
+// since usernames and user agents are chosen by the caller
+func csvValue(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
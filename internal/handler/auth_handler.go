@@ -3,6 +3,9 @@ package handler
 import (
 	"api-stockflow/internal/domain"
 	"api-stockflow/internal/usecase"
+	"errors"
+	"math"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -10,12 +13,14 @@ import (
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
 	authUseCase usecase.AuthUseCase
+	cookie      RefreshCookieConfig
 }
 
 // NewAuthHandler creates a new authentication handler
-func NewAuthHandler(authUseCase usecase.AuthUseCase) *AuthHandler {
+func NewAuthHandler(authUseCase usecase.AuthUseCase, cookie RefreshCookieConfig) *AuthHandler {
 	return &AuthHandler{
 		authUseCase: authUseCase,
+		cookie:      cookie,
 	}
 }
 
@@ -31,8 +36,12 @@ type RefreshTokenRequest struct {
 }
 
 // LogoutRequest represents logout request body
-type LogoutRequest struct {
-	RefreshToken string `json:"refresh_token"`
+type LogoutRequest = RefreshTokenRequest
+
+// MFALoginRequest represents the second login step request body
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
 }
 
 // Login handles user login
@@ -54,14 +63,40 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Login
-	response, err := h.authUseCase.Login(c.Context(), req.Username, req.Password)
+	response, err := h.authUseCase.Login(c.Context(), req.Username, req.Password, clientInfo(c))
 	if err != nil {
+		var lockoutErr *domain.LockoutError
+		if errors.As(err, &lockoutErr) {
+			return lockoutResponse(c, lockoutErr)
+		}
 		if err == domain.ErrInvalidCredentials {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "fail",
 				"message": "Invalid credentials",
 			})
 		}
+		if err == domain.ErrAccountSuspended || err == domain.ErrAccountDeactivated {
+			return inactiveAccountResponse(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	if response.MFARequired {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status": "success",
+			"data": fiber.Map{
+				"mfa_required":            true,
+				"mfa_enrollment_required": response.MFAEnrollmentRequired,
+				"mfa_token":               response.MFAToken,
+			},
+		})
+	}
+
+	data, err := h.cookie.tokenData(c, response.AccessToken, response.RefreshToken)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Internal server error",
@@ -70,17 +105,156 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status": "success",
-		"data": fiber.Map{
-			"access_token":  response.AccessToken,
-			"refresh_token": response.RefreshToken,
-		},
+		"data":   data,
+	})
+}
+
+// VerifyMFALogin handles the second login step of an enrolled user
+func (h *AuthHandler) VerifyMFALogin(c *fiber.Ctx) error {
+	var req MFALoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.MFAToken == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "MFA token and code are required",
+		})
+	}
+
+	response, err := h.authUseCase.VerifyMFALogin(c.Context(), req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		return mfaLoginError(c, err)
+	}
+
+	data, err := h.cookie.tokenData(c, response.AccessToken, response.RefreshToken)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   data,
+	})
+}
+
+// BeginMFAEnrollment handles enrolling a second factor during login
+func (h *AuthHandler) BeginMFAEnrollment(c *fiber.Ctx) error {
+	var req MFALoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.MFAToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "MFA token is required",
+		})
+	}
+
+	enrollment, err := h.authUseCase.BeginMFAEnrollment(c.Context(), req.MFAToken)
+	if err != nil {
+		return mfaLoginError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   enrollment,
+	})
+}
+
+// CompleteMFAEnrollment handles confirming a second factor enrolled during login
+func (h *AuthHandler) CompleteMFAEnrollment(c *fiber.Ctx) error {
+	var req MFALoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.MFAToken == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "MFA token and code are required",
+		})
+	}
+
+	response, err := h.authUseCase.CompleteMFAEnrollment(c.Context(), req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		return mfaLoginError(c, err)
+	}
+
+	data, err := h.cookie.tokenData(c, response.AccessToken, response.RefreshToken)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+	data["recovery_codes"] = response.RecoveryCodes
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   data,
+	})
+}
+
+// mfaLoginError maps errors of the second login step to responses
+func mfaLoginError(c *fiber.Ctx, err error) error {
+	var lockoutErr *domain.LockoutError
+	if errors.As(err, &lockoutErr) {
+		return lockoutResponse(c, lockoutErr)
+	}
+
+	switch err {
+	case domain.ErrInvalidToken, domain.ErrUserNotFound:
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid or expired MFA token, please login again",
+		})
+	case domain.ErrAccountSuspended, domain.ErrAccountDeactivated:
+		return inactiveAccountResponse(c, err)
+	case domain.ErrInvalidMFACode:
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid two-factor authentication code",
+		})
+	case domain.ErrMFANotEnrolled:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is not enrolled",
+		})
+	case domain.ErrMFAAlreadyEnabled:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is already enabled",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
 	})
 }
 
 // RefreshToken handles token refresh
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
-	var req RefreshTokenRequest
-	if err := c.BodyParser(&req); err != nil {
+	refreshToken, err := h.cookie.refreshTokenFromRequest(c)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Invalid request body",
@@ -88,7 +262,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	}
 
 	// Validate request
-	if req.RefreshToken == "" {
+	if refreshToken == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Refresh token is required",
@@ -96,14 +270,31 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	}
 
 	// Refresh token
-	response, err := h.authUseCase.RefreshToken(c.Context(), req.RefreshToken)
+	response, err := h.authUseCase.RefreshToken(c.Context(), refreshToken, clientInfo(c))
 	if err != nil {
-		if err == domain.ErrInvalidToken || err == domain.ErrRefreshTokenNotFound {
+		if err == domain.ErrRefreshTokenReused {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Refresh token has already been used, please login again",
+			})
+		}
+		if err == domain.ErrInvalidToken || err == domain.ErrRefreshTokenNotFound || err == domain.ErrUserNotFound {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "fail",
 				"message": "Invalid or expired refresh token",
 			})
 		}
+		if err == domain.ErrAccountSuspended || err == domain.ErrAccountDeactivated {
+			return inactiveAccountResponse(c, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	data, err := h.cookie.tokenData(c, response.AccessToken, response.RefreshToken)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Internal server error",
@@ -112,16 +303,14 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status": "success",
-		"data": fiber.Map{
-			"access_token": response.AccessToken,
-		},
+		"data":   data,
 	})
 }
 
 // Logout handles user logout
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
-	var req LogoutRequest
-	if err := c.BodyParser(&req); err != nil {
+	refreshToken, err := h.cookie.refreshTokenFromRequest(c)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Invalid request body",
@@ -129,7 +318,7 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	}
 
 	// Validate request
-	if req.RefreshToken == "" {
+	if refreshToken == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Refresh token is required",
@@ -137,9 +326,11 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	}
 
 	// Logout
-	err := h.authUseCase.Logout(c.Context(), req.RefreshToken)
+	err = h.authUseCase.Logout(c.Context(), refreshToken, clientInfo(c))
 	if err != nil {
-		if err == domain.ErrInvalidToken || err == domain.ErrRefreshTokenNotFound {
+		if err == domain.ErrInvalidToken || err == domain.ErrRefreshTokenNotFound || err == domain.ErrRefreshTokenReused {
+			// The cookie is of no use anymore
+			h.cookie.clearCookies(c)
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "fail",
 				"message": "Invalid or expired refresh token",
@@ -151,13 +342,15 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 		})
 	}
 
+	h.cookie.clearCookies(c)
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Successfully logged out",
 	})
 }
 
-// Register handles user registration
+// Register handles open self-registration, which only grants the staff role
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	var req usecase.RegisterRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -175,14 +368,19 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
-	// Default role is staff if not provided
-	if req.Role == "" {
-		req.Role = domain.RoleStaff
-	}
-
 	// Register
-	err := h.authUseCase.Register(c.Context(), &req)
+	err := h.authUseCase.Register(c.Context(), &req, clientInfo(c))
 	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return validationResponse(c, validationErr)
+		}
+		if err == domain.ErrRegistrationClosed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Self-registration is disabled, please ask an admin for an invitation",
+			})
+		}
 		if err == domain.ErrUsernameAlreadyUsed {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 				"status":  "fail",
@@ -209,10 +407,156 @@ func (h *AuthHandler) GetProfile(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status": "success",
 		"data": fiber.Map{
-			"id":       user.ID,
-			"username": user.Username,
-			"fullname": user.Fullname,
-			"role":     user.Role,
+			"id":          user.ID,
+			"username":    user.Username,
+			"fullname":    user.Fullname,
+			"role":        user.Role,
+			"roles":       user.Roles,
+			"permissions": user.Permissions,
 		},
 	})
 }
+
+// ListSessions handles listing the sessions of the current user
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+	sessionID, _ := c.Locals("session_id").(string)
+
+	sessions, err := h.authUseCase.ListSessions(c.Context(), user.ID, sessionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"sessions": sessions,
+		},
+	})
+}
+
+// RevokeSession handles revoking one session of the current user
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	err := h.authUseCase.RevokeSession(c.Context(), user.ID, c.Params("id"))
+	if err != nil {
+		if err == domain.ErrSessionNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Session not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Session revoked",
+	})
+}
+
+// RevokeOtherSessions handles revoking every session except the current one
+func (h *AuthHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+	sessionID, _ := c.Locals("session_id").(string)
+
+	revoked, err := h.authUseCase.RevokeOtherSessions(c.Context(), user.ID, sessionID)
+	if err != nil {
+		if err == domain.ErrSessionNotFound {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Current session is unknown, please login again",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"revoked": revoked,
+		},
+	})
+}
+
+// UnlockAccount handles an admin clearing the lockout of a username
+func (h *AuthHandler) UnlockAccount(c *fiber.Ctx) error {
+	err := h.authUseCase.UnlockAccount(c.Context(), c.Params("username"))
+	if err != nil {
+		if err == domain.ErrLoginAttemptNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Account has no failed login attempts",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Account unlocked",
+	})
+}
+
+// lockoutResponse responds 423 for a locked account and 429 for a throttled address
+func lockoutResponse(c *fiber.Ctx, lockoutErr *domain.LockoutError) error {
+	retryAfter := int(math.Ceil(lockoutErr.RetryAfter.Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+	if errors.Is(lockoutErr, domain.ErrAccountLocked) {
+		return c.Status(fiber.StatusLocked).JSON(fiber.Map{
+			"status":      "fail",
+			"message":     "Account is temporarily locked due to too many failed login attempts",
+			"retry_after": retryAfter,
+		})
+	}
+
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"status":      "fail",
+		"message":     "Too many login attempts, please try again later",
+		"retry_after": retryAfter,
+	})
+}
+
+// validationResponse responds 422 with every rejected field
+func validationResponse(c *fiber.Ctx, validationErr *domain.ValidationError) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+		"status":  "fail",
+		"message": "Validation failed",
+		"errors":  validationErr.Errors,
+	})
+}
+
+// clientInfo extracts the client metadata recorded on sessions
+func clientInfo(c *fiber.Ctx) domain.ClientInfo {
+	return domain.ClientInfo{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	}
+}
+
+// inactiveAccountResponse rejects a suspended or deactivated account
+func inactiveAccountResponse(c *fiber.Ctx, err error) error {
+	message := "Account is suspended, please contact Admin"
+	if err == domain.ErrAccountDeactivated {
+		message = "Account is deactivated"
+	}
+
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  "fail",
+		"message": message,
+	})
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CategoryHandler handles product category HTTP requests
+type CategoryHandler struct {
+	categoryUseCase usecase.CategoryUseCase
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryUseCase usecase.CategoryUseCase) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUseCase: categoryUseCase,
+	}
+}
+
+// List handles listing categories depth-first as a flat list, optionally below ?root_id=
+func (h *CategoryHandler) List(c *fiber.Ctx) error {
+	categories, err := h.categoryUseCase.List(c.Context(), c.Query("root_id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   categories,
+	})
+}
+
+// Tree handles listing categories nested under their parents, optionally below ?root_id=
+func (h *CategoryHandler) Tree(c *fiber.Ctx) error {
+	tree, err := h.categoryUseCase.Tree(c.Context(), c.Query("root_id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   tree,
+	})
+}
+
+// Get handles getting one category with its path
+func (h *CategoryHandler) Get(c *fiber.Ctx) error {
+	category, err := h.categoryUseCase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   category,
+	})
+}
+
+// Create handles adding a category
+func (h *CategoryHandler) Create(c *fiber.Ctx) error {
+	var req usecase.CreateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	category, err := h.categoryUseCase.Create(c.Context(), &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   category,
+	})
+}
+
+// Update handles renaming a category
+func (h *CategoryHandler) Update(c *fiber.Ctx) error {
+	var req usecase.UpdateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	category, err := h.categoryUseCase.Update(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   category,
+	})
+}
+
+// Move handles moving a category with its subtree to another parent
+func (h *CategoryHandler) Move(c *fiber.Ctx) error {
+	var req usecase.MoveCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	category, err := h.categoryUseCase.Move(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   category,
+	})
+}
+
+// Delete handles deleting an empty category
+func (h *CategoryHandler) Delete(c *fiber.Ctx) error {
+	err := h.categoryUseCase.Delete(c.Context(), c.Params("id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Category deleted",
+	})
+}
+
+// categoryError maps category errors to responses
+func categoryError(c *fiber.Ctx, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationResponse(c, validationErr)
+	}
+
+	switch err {
+	case domain.ErrCategoryNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Category not found",
+		})
+	case domain.ErrCategoryAlreadyExists:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "A category with the same name already exists under this parent",
+		})
+	case domain.ErrCategoryCycle:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "A category cannot be moved below itself or one of its subcategories",
+		})
+	case domain.ErrCategoryInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Category still holds subcategories or products",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
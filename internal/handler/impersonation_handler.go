@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImpersonationHandler handles admin impersonation HTTP requests
+type ImpersonationHandler struct {
+	impersonationUseCase usecase.ImpersonationUseCase
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationUseCase usecase.ImpersonationUseCase) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationUseCase: impersonationUseCase,
+	}
+}
+
+// StartImpersonationRequest represents impersonation request body
+type StartImpersonationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Start handles issuing an impersonation token for a user
+func (h *ImpersonationHandler) Start(c *fiber.Ctx) error {
+	actor := c.Locals("user").(*domain.User)
+
+	// Impersonating from an impersonation token would hide the admin
+	if _, impersonating := c.Locals("impersonator").(*domain.User); impersonating {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "End the current impersonation first",
+		})
+	}
+
+	var req StartImpersonationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if strings.TrimSpace(req.Reason) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Reason is required",
+		})
+	}
+
+	response, err := h.impersonationUseCase.Start(c.Context(), actor, c.Params("id"), req.Reason, clientInfo(c))
+	if err != nil {
+		switch err {
+		case domain.ErrUserNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "User not found",
+			})
+		case domain.ErrCannotImpersonate:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "You cannot impersonate yourself or another administrator",
+			})
+		case domain.ErrAccountSuspended, domain.ErrAccountDeactivated:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Only active users can be impersonated",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// End handles ending the impersonation of the token in use
+func (h *ImpersonationHandler) End(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+	impersonator, _ := c.Locals("impersonator").(*domain.User)
+	sessionID, _ := c.Locals("session_id").(string)
+
+	err := h.impersonationUseCase.End(c.Context(), impersonator, user, sessionID, clientInfo(c))
+	if err != nil {
+		if err == domain.ErrNotImpersonating {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Not impersonating a user",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Impersonation ended",
+	})
+}
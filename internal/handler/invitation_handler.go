@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InvitationHandler handles invitation HTTP requests
+type InvitationHandler struct {
+	invitationUseCase usecase.InvitationUseCase
+}
+
+// NewInvitationHandler creates a new invitation handler
+func NewInvitationHandler(invitationUseCase usecase.InvitationUseCase) *InvitationHandler {
+	return &InvitationHandler{
+		invitationUseCase: invitationUseCase,
+	}
+}
+
+// Create handles creating an invitation for a role
+func (h *InvitationHandler) Create(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var req usecase.CreateInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role is required",
+		})
+	}
+
+	invitation, err := h.invitationUseCase.Create(c.Context(), &req, user.ID)
+	if err != nil {
+		return invitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   invitation,
+	})
+}
+
+// List handles listing invitations, only pending ones unless ?all=true
+func (h *InvitationHandler) List(c *fiber.Ctx) error {
+	invitations, err := h.invitationUseCase.List(c.Context(), !c.QueryBool("all"))
+	if err != nil {
+		return invitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"invitations": invitations,
+		},
+	})
+}
+
+// Revoke handles deleting an invitation
+func (h *InvitationHandler) Revoke(c *fiber.Ctx) error {
+	err := h.invitationUseCase.Revoke(c.Context(), c.Params("id"))
+	if err != nil {
+		return invitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Invitation revoked",
+	})
+}
+
+// Accept handles registering with an invitation token
+func (h *InvitationHandler) Accept(c *fiber.Ctx) error {
+	var req usecase.AcceptInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.Token == "" || req.Username == "" || req.Password == "" || req.Fullname == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Token, username, password, and fullname are required",
+		})
+	}
+
+	err := h.invitationUseCase.Accept(c.Context(), &req)
+	if err != nil {
+		return invitationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "User registered successfully",
+	})
+}
+
+// invitationError maps invitation errors to responses
+func invitationError(c *fiber.Ctx, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationResponse(c, validationErr)
+	}
+
+	switch err {
+	case domain.ErrInvitationNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invitation not found",
+		})
+	case domain.ErrInvitationInvalid:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid, expired or already used invitation",
+		})
+	case domain.ErrRoleNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	case domain.ErrUsernameAlreadyUsed:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Username already used",
+		})
+	case domain.ErrBadRequest:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invitation lifetime exceeds the allowed maximum",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
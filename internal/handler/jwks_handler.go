@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"api-stockflow/internal/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWKSHandler publishes the public keys used to sign access tokens
+type JWKSHandler struct {
+	tokenManager security.TokenManager
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(tokenManager security.TokenManager) *JWKSHandler {
+	return &JWKSHandler{
+		tokenManager: tokenManager,
+	}
+}
+
+// GetKeys handles serving the JSON Web Key Set.
+// The document is returned as is because JWKS consumers expect the bare key set.
+func (h *JWKSHandler) GetKeys(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.tokenManager.JWKS())
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocationHandler handles warehouse location HTTP requests
+type LocationHandler struct {
+	locationUseCase usecase.LocationUseCase
+}
+
+// NewLocationHandler creates a new location handler
+func NewLocationHandler(locationUseCase usecase.LocationUseCase) *LocationHandler {
+	return &LocationHandler{
+		locationUseCase: locationUseCase,
+	}
+}
+
+// List handles listing the locations of the warehouses the user may operate with ?search=, ?warehouse_id=,
+// ?parent_id=, ?level=, ?type=, ?active=, ?page= and ?page_size=
+func (h *LocationHandler) List(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var active *bool
+	if value := c.Query("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid active, use true or false",
+			})
+		}
+		active = &parsed
+	}
+
+	result, err := h.locationUseCase.List(c.Context(), user, &usecase.ListLocationsRequest{
+		Search:      c.Query("search"),
+		WarehouseID: c.Query("warehouse_id"),
+		ParentID:    c.Query("parent_id"),
+		Level:       domain.LocationLevel(c.Query("level")),
+		Type:        domain.LocationType(c.Query("type")),
+		Active:      active,
+		Page:        c.QueryInt("page", 1),
+		PageSize:    c.QueryInt("page_size"),
+	})
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// Get handles getting one location
+func (h *LocationHandler) Get(c *fiber.Ctx) error {
+	location, err := h.locationUseCase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   location,
+	})
+}
+
+// Create handles adding a zone, aisle or bin to a warehouse
+func (h *LocationHandler) Create(c *fiber.Ctx) error {
+	var req usecase.CreateLocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	location, err := h.locationUseCase.Create(c.Context(), &req)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   location,
+	})
+}
+
+// Update handles editing a location
+func (h *LocationHandler) Update(c *fiber.Ctx) error {
+	var req usecase.UpdateLocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	location, err := h.locationUseCase.Update(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   location,
+	})
+}
+
+// Delete handles deleting a location
+func (h *LocationHandler) Delete(c *fiber.Ctx) error {
+	err := h.locationUseCase.Delete(c.Context(), c.Params("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Location deleted",
+	})
+}
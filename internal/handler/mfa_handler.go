@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MFAHandler handles two-factor authentication HTTP requests of logged in users
+type MFAHandler struct {
+	mfaUseCase usecase.MFAUseCase
+}
+
+// NewMFAHandler creates a new two-factor authentication handler
+func NewMFAHandler(mfaUseCase usecase.MFAUseCase) *MFAHandler {
+	return &MFAHandler{
+		mfaUseCase: mfaUseCase,
+	}
+}
+
+// MFACodeRequest represents a request body carrying a two-factor code
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+// Status handles getting the two-factor authentication state of the current user
+func (h *MFAHandler) Status(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	status, err := h.mfaUseCase.Status(c.Context(), user.ID)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   status,
+	})
+}
+
+// Enroll handles starting enrollment of an authenticator app
+func (h *MFAHandler) Enroll(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	enrollment, err := h.mfaUseCase.Enroll(c.Context(), user.ID)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   enrollment,
+	})
+}
+
+// Activate handles confirming enrollment with a code from the authenticator app
+func (h *MFAHandler) Activate(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	code, ok := parseMFACode(c)
+	if !ok {
+		return nil
+	}
+
+	recoveryCodes, err := h.mfaUseCase.Activate(c.Context(), user.ID, code)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"recovery_codes": recoveryCodes,
+		},
+	})
+}
+
+// Disable handles removing the second factor of the current user
+func (h *MFAHandler) Disable(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	code, ok := parseMFACode(c)
+	if !ok {
+		return nil
+	}
+
+	err := h.mfaUseCase.Disable(c.Context(), user.ID, code)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes handles replacing the recovery codes of the current user
+func (h *MFAHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	code, ok := parseMFACode(c)
+	if !ok {
+		return nil
+	}
+
+	recoveryCodes, err := h.mfaUseCase.RegenerateRecoveryCodes(c.Context(), user.ID, code)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"recovery_codes": recoveryCodes,
+		},
+	})
+}
+
+// parseMFACode reads the code from the request body.
+// It writes the error response itself and returns false when the body is invalid.
+func parseMFACode(c *fiber.Ctx) (string, bool) {
+	var req MFACodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+		return "", false
+	}
+
+	// Validate request
+	if req.Code == "" {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Code is required",
+		})
+		return "", false
+	}
+
+	return req.Code, true
+}
+
+// mfaError maps two-factor authentication errors to responses
+func mfaError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrInvalidMFACode:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid two-factor authentication code",
+		})
+	case domain.ErrMFANotEnrolled:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is not enrolled",
+		})
+	case domain.ErrMFAAlreadyEnabled:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is already enabled",
+		})
+	case domain.ErrMFARequiredByPolicy:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is required for your role",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OIDCStateCookieConfig configures the HttpOnly cookie binding a single sign-on
+// login to the browser that started it. A callback without the cookie of its
+// state is refused, so an attacker cannot complete a login in a victim's browser.
+type OIDCStateCookieConfig struct {
+	Name     string
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite string
+	MaxAge   time.Duration
+}
+
+func (cfg OIDCStateCookieConfig) set(c *fiber.Ctx, state string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     cfg.Name,
+		Value:    state,
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		Expires:  expires,
+		Secure:   cfg.Secure,
+		HTTPOnly: true,
+		SameSite: cfg.SameSite,
+	})
+}
+
+// OIDCHandler handles single sign-on HTTP requests
+type OIDCHandler struct {
+	oidcUseCase usecase.OIDCUseCase
+	cookie      RefreshCookieConfig
+	stateCookie OIDCStateCookieConfig
+}
+
+// NewOIDCHandler creates a new single sign-on handler
+func NewOIDCHandler(oidcUseCase usecase.OIDCUseCase, cookie RefreshCookieConfig, stateCookie OIDCStateCookieConfig) *OIDCHandler {
+	return &OIDCHandler{
+		oidcUseCase: oidcUseCase,
+		cookie:      cookie,
+		stateCookie: stateCookie,
+	}
+}
+
+// OIDCCallbackRequest represents the parameters the identity provider redirected back with
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// Authorize handles starting a single sign-on login
+func (h *OIDCHandler) Authorize(c *fiber.Ctx) error {
+	authorization, err := h.oidcUseCase.Authorize(c.Context())
+	if err != nil {
+		if err == domain.ErrOIDCLoginFailed {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Identity provider is unavailable",
+			})
+		}
+		return oidcError(c, err)
+	}
+
+	h.stateCookie.set(c, authorization.State, time.Now().Add(h.stateCookie.MaxAge))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   authorization,
+	})
+}
+
+// Callback handles completing a single sign-on login
+func (h *OIDCHandler) Callback(c *fiber.Ctx) error {
+	var req OIDCCallbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.Code == "" || req.State == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Code and state are required",
+		})
+	}
+
+	// The state cookie is good for one attempt, successful or not
+	browserState := c.Cookies(h.stateCookie.Name)
+	h.stateCookie.set(c, "", time.Unix(0, 0))
+
+	response, err := h.oidcUseCase.Callback(c.Context(), req.Code, req.State, browserState, clientInfo(c))
+	if err != nil {
+		return oidcError(c, err)
+	}
+
+	data, err := h.cookie.tokenData(c, response.AccessToken, response.RefreshToken)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   data,
+	})
+}
+
+// oidcError maps single sign-on errors to responses
+func oidcError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrOIDCStateInvalid:
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid or expired login state, please login again",
+		})
+	case domain.ErrOIDCLoginFailed:
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login at the identity provider failed",
+		})
+	case domain.ErrOIDCUsernameMissing:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Identity provider did not return a usable username",
+		})
+	case domain.ErrOIDCMFAMissing:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Your role requires two-factor authentication, please login with a second factor at the identity provider",
+		})
+	case domain.ErrUsernameAlreadyUsed:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Username already used by a local account, please contact Admin",
+		})
+	case domain.ErrAccountSuspended, domain.ErrAccountDeactivated:
+		return inactiveAccountResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PasswordHandler handles password management HTTP requests
+type PasswordHandler struct {
+	passwordUseCase usecase.PasswordUseCase
+}
+
+// NewPasswordHandler creates a new password handler
+func NewPasswordHandler(passwordUseCase usecase.PasswordUseCase) *PasswordHandler {
+	return &PasswordHandler{
+		passwordUseCase: passwordUseCase,
+	}
+}
+
+// PasswordResetRequest represents admin password reset request body
+type PasswordResetRequest struct {
+	Username string `json:"username"`
+}
+
+// ChangePassword handles password change of the current user
+func (h *PasswordHandler) ChangePassword(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var req usecase.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.OldPassword == "" || req.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Old password and new password are required",
+		})
+	}
+
+	err := h.passwordUseCase.ChangePassword(c.Context(), user.ID, &req)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return validationResponse(c, validationErr)
+		}
+		if err == domain.ErrPasswordMismatch {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Old password is incorrect",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Password changed successfully, please login again",
+	})
+}
+
+// RequestReset handles an admin starting a password reset for a user
+func (h *PasswordHandler) RequestReset(c *fiber.Ctx) error {
+	admin := c.Locals("user").(*domain.User)
+
+	var req PasswordResetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.Username == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Username is required",
+		})
+	}
+
+	err := h.passwordUseCase.RequestReset(c.Context(), req.Username, admin.ID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "User not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Password reset link sent",
+	})
+}
+
+// ResetPassword handles setting a new password with a reset token
+func (h *PasswordHandler) ResetPassword(c *fiber.Ctx) error {
+	var req usecase.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.Token == "" || req.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Token and new password are required",
+		})
+	}
+
+	err := h.passwordUseCase.ResetPassword(c.Context(), &req)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return validationResponse(c, validationErr)
+		}
+		if err == domain.ErrResetTokenInvalid || err == domain.ErrUserNotFound {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid or expired reset token",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Password has been reset, please login with the new password",
+	})
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProductHandler handles product catalog HTTP requests
+type ProductHandler struct {
+	productUseCase usecase.ProductUseCase
+}
+
+// NewProductHandler creates a new product handler
+func NewProductHandler(productUseCase usecase.ProductUseCase) *ProductHandler {
+	return &ProductHandler{
+		productUseCase: productUseCase,
+	}
+}
+
+// List handles listing products with ?search=, ?category_id=, ?subcategories=, ?unit_of_measure=, ?active=, ?page= and ?page_size=.
+// Products of subcategories are included unless ?subcategories=false.
+func (h *ProductHandler) List(c *fiber.Ctx) error {
+	var active *bool
+	if value := c.Query("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid active, use true or false",
+			})
+		}
+		active = &parsed
+	}
+
+	subcategories, err := strconv.ParseBool(c.Query("subcategories", "true"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid subcategories, use true or false",
+		})
+	}
+
+	result, err := h.productUseCase.List(c.Context(), &usecase.ListProductsRequest{
+		Search:               c.Query("search"),
+		CategoryID:           c.Query("category_id"),
+		IncludeSubcategories: subcategories,
+		UnitOfMeasure:        c.Query("unit_of_measure"),
+		Active:               active,
+		Page:                 c.QueryInt("page", 1),
+		PageSize:             c.QueryInt("page_size"),
+	})
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// Get handles getting one product
+func (h *ProductHandler) Get(c *fiber.Ctx) error {
+	product, err := h.productUseCase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   product,
+	})
+}
+
+// Create handles adding a product to the catalog
+func (h *ProductHandler) Create(c *fiber.Ctx) error {
+	var req usecase.CreateProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	product, err := h.productUseCase.Create(c.Context(), &req)
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   product,
+	})
+}
+
+// Update handles editing a product
+func (h *ProductHandler) Update(c *fiber.Ctx) error {
+	var req usecase.UpdateProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	product, err := h.productUseCase.Update(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   product,
+	})
+}
+
+// Delete handles deleting a product
+func (h *ProductHandler) Delete(c *fiber.Ctx) error {
+	err := h.productUseCase.Delete(c.Context(), c.Params("id"))
+	if err != nil {
+		return productError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Product deleted",
+	})
+}
+
+// productError maps product catalog errors to responses
+func productError(c *fiber.Ctx, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationResponse(c, validationErr)
+	}
+
+	switch err {
+	case domain.ErrProductNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Product not found",
+		})
+	case domain.ErrSKUAlreadyUsed:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "SKU already used",
+		})
+	case domain.ErrBarcodeAlreadyUsed:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Barcode already used",
+		})
+	case domain.ErrProductInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Product has stock movements, deactivate it instead",
+		})
+	case domain.ErrCategoryNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Category not found",
+		})
+	case domain.ErrUoMNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unit of measure not found",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"api-stockflow/internal/security"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RefreshCookieConfig configures cookie mode, in which the refresh token is
+// kept in an HttpOnly cookie instead of the JSON body. Cookie mode comes with
+// double-submit CSRF protection: a readable CSRF cookie whose value must be
+// echoed in the X-CSRF-Token header.
+type RefreshCookieConfig struct {
+	Enabled  bool
+	Name     string
+	CSRFName string
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite string
+	MaxAge   time.Duration
+}
+
+// tokenData returns the response data for a new token pair.
+// In cookie mode the refresh token is only sent as cookie.
+func (cfg RefreshCookieConfig) tokenData(c *fiber.Ctx, accessToken, refreshToken string) (fiber.Map, error) {
+	if !cfg.Enabled {
+		return fiber.Map{
+			"access_token":  accessToken,
+			"refresh_token": refreshToken,
+		}, nil
+	}
+
+	// The CSRF token rotates together with the refresh token
+	csrfToken, err := security.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.setCookies(c, refreshToken, csrfToken, time.Now().Add(cfg.MaxAge))
+
+	return fiber.Map{
+		"access_token": accessToken,
+		"csrf_token":   csrfToken,
+	}, nil
+}
+
+// refreshTokenFromRequest reads the refresh token from the cookie in cookie
+// mode and from the JSON body otherwise
+func (cfg RefreshCookieConfig) refreshTokenFromRequest(c *fiber.Ctx) (string, error) {
+	if cfg.Enabled {
+		return c.Cookies(cfg.Name), nil
+	}
+
+	var req RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return "", err
+	}
+
+	return req.RefreshToken, nil
+}
+
+// clearCookies removes the refresh and CSRF cookies in cookie mode
+func (cfg RefreshCookieConfig) clearCookies(c *fiber.Ctx) {
+	if cfg.Enabled {
+		cfg.setCookies(c, "", "", time.Unix(0, 0))
+	}
+}
+
+func (cfg RefreshCookieConfig) setCookies(c *fiber.Ctx, refreshToken, csrfToken string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     cfg.Name,
+		Value:    refreshToken,
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		Expires:  expires,
+		Secure:   cfg.Secure,
+		HTTPOnly: true,
+		SameSite: cfg.SameSite,
+	})
+
+	// Readable by the SPA on every page so it can send the X-CSRF-Token header
+	c.Cookie(&fiber.Cookie{
+		Name:     cfg.CSRFName,
+		Value:    csrfToken,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		Expires:  expires,
+		Secure:   cfg.Secure,
+		HTTPOnly: false,
+		SameSite: cfg.SameSite,
+	})
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleHandler handles role management HTTP requests
+type RoleHandler struct {
+	roleUseCase usecase.RoleUseCase
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(roleUseCase usecase.RoleUseCase) *RoleHandler {
+	return &RoleHandler{
+		roleUseCase: roleUseCase,
+	}
+}
+
+// UserRolesRequest represents user role assignment request body
+type UserRolesRequest struct {
+	Roles []domain.UserRole `json:"roles"`
+}
+
+// ListRoles handles listing every role with its permissions
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.roleUseCase.ListRoles(c.Context())
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"roles": roles,
+		},
+	})
+}
+
+// GetRole handles getting one role
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	role, err := h.roleUseCase.GetRole(c.Context(), domain.UserRole(c.Params("name")))
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   role,
+	})
+}
+
+// CreateRole handles creating a role
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var req usecase.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role name is required",
+		})
+	}
+
+	role, err := h.roleUseCase.CreateRole(c.Context(), &req)
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   role,
+	})
+}
+
+// UpdateRole handles replacing the description and permissions of a role
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	var req usecase.RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	role, err := h.roleUseCase.UpdateRole(c.Context(), domain.UserRole(c.Params("name")), &req)
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   role,
+	})
+}
+
+// DeleteRole handles deleting a role that is not assigned to any user
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	err := h.roleUseCase.DeleteRole(c.Context(), domain.UserRole(c.Params("name")))
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Role deleted",
+	})
+}
+
+// ListPermissions handles listing every known permission
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := h.roleUseCase.ListPermissions(c.Context())
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"permissions": permissions,
+		},
+	})
+}
+
+// GetUserRoles handles listing the roles of a user
+func (h *RoleHandler) GetUserRoles(c *fiber.Ctx) error {
+	roles, err := h.roleUseCase.GetUserRoles(c.Context(), c.Params("id"))
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"roles": roles,
+		},
+	})
+}
+
+// SetUserRoles handles replacing the roles of a user
+func (h *RoleHandler) SetUserRoles(c *fiber.Ctx) error {
+	var req UserRolesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	err := h.roleUseCase.SetUserRoles(c.Context(), c.Params("id"), req.Roles)
+	if err != nil {
+		return roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "User roles updated",
+	})
+}
+
+// roleError maps role management errors to responses
+func roleError(c *fiber.Ctx, err error) error {
+	switch err {
+	case domain.ErrRoleNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	case domain.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	case domain.ErrRoleAlreadyExists:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role already exists",
+		})
+	case domain.ErrRoleInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role is still assigned to users",
+		})
+	case domain.ErrPermissionNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unknown permission",
+		})
+	case domain.ErrUserRolesRequired:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "At least one role is required",
+		})
+	case domain.ErrBadRequest:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role name must be 2-20 lowercase letters, digits, '-' or '_'",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StockHandler handles stock ledger HTTP requests
+type StockHandler struct {
+	stockUseCase usecase.StockUseCase
+}
+
+// NewStockHandler creates a new stock handler
+func NewStockHandler(stockUseCase usecase.StockUseCase) *StockHandler {
+	return &StockHandler{
+		stockUseCase: stockUseCase,
+	}
+}
+
+// Post handles posting stock movements
+func (h *StockHandler) Post(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var req usecase.PostStockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	posting, err := h.stockUseCase.Post(c.Context(), user, &req)
+	if err != nil {
+		return stockError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   posting,
+	})
+}
+
+// Reverse handles posting the reversal of a posting
+func (h *StockHandler) Reverse(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	// The body is optional
+	var req usecase.ReverseStockRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid request body",
+			})
+		}
+	}
+
+	posting, err := h.stockUseCase.Reverse(c.Context(), user, c.Params("id"), &req)
+	if err != nil {
+		return stockError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   posting,
+	})
+}
+
+// ListMovements handles listing stock movements, latest first, with ?product_id=, ?location_id=, ?warehouse_id=,
+// ?posting_id=, ?type=, ?reference_type=, ?reference_id=, ?from=, ?to=, ?page= and ?page_size=.
+// from and to are RFC 3339 times; to is exclusive.
+func (h *StockHandler) ListMovements(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var times [2]*time.Time
+	for i, key := range []string{"from", "to"} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid " + key + ", use an RFC 3339 time like 2024-01-31T00:00:00Z",
+			})
+		}
+		times[i] = &parsed
+	}
+
+	result, err := h.stockUseCase.ListMovements(c.Context(), user, &usecase.ListMovementsRequest{
+		ProductID:     c.Query("product_id"),
+		LocationID:    c.Query("location_id"),
+		WarehouseID:   c.Query("warehouse_id"),
+		PostingID:     c.Query("posting_id"),
+		Type:          domain.MovementType(c.Query("type")),
+		ReferenceType: c.Query("reference_type"),
+		ReferenceID:   c.Query("reference_id"),
+		From:          times[0],
+		To:            times[1],
+		Page:          c.QueryInt("page", 1),
+		PageSize:      c.QueryInt("page_size"),
+	})
+	if err != nil {
+		return stockError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// ListBalances handles listing on-hand quantities with ?product_id=, ?location_id=, ?warehouse_id=,
+// ?include_zero=, ?page= and ?page_size=
+func (h *StockHandler) ListBalances(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	includeZero, err := strconv.ParseBool(c.Query("include_zero", "false"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid include_zero, use true or false",
+		})
+	}
+
+	result, err := h.stockUseCase.ListBalances(c.Context(), user, &usecase.ListBalancesRequest{
+		ProductID:   c.Query("product_id"),
+		LocationID:  c.Query("location_id"),
+		WarehouseID: c.Query("warehouse_id"),
+		IncludeZero: includeZero,
+		Page:        c.QueryInt("page", 1),
+		PageSize:    c.QueryInt("page_size"),
+	})
+	if err != nil {
+		return stockError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// stockError maps stock ledger errors to responses
+func stockError(c *fiber.Ctx, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationResponse(c, validationErr)
+	}
+
+	switch err {
+	case domain.ErrPostingNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Stock posting not found",
+		})
+	case domain.ErrWarehouseNotAssigned:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "You are not assigned to this warehouse",
+		})
+	case domain.ErrPostingReversed:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Stock posting is already reversed",
+		})
+	case domain.ErrCannotReverseReversal:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "A reversal cannot be reversed, post the movements again instead",
+		})
+	case domain.ErrInsufficientStock:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Not enough stock at a location for the movements",
+		})
+	case domain.ErrQuantityOutOfRange:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "The movements would take a balance out of range",
+		})
+	case domain.ErrProductNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Product not found",
+		})
+	case domain.ErrLocationNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Location not found",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
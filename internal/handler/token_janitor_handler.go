@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"api-stockflow/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TokenJanitorHandler handles refresh token cleanup HTTP requests
+type TokenJanitorHandler struct {
+	tokenJanitor usecase.TokenJanitor
+}
+
+// NewTokenJanitorHandler creates a new refresh token cleanup handler
+func NewTokenJanitorHandler(tokenJanitor usecase.TokenJanitor) *TokenJanitorHandler {
+	return &TokenJanitorHandler{
+		tokenJanitor: tokenJanitor,
+	}
+}
+
+// Metrics handles reporting the refresh token cleanup metrics of this instance
+func (h *TokenJanitorHandler) Metrics(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   h.tokenJanitor.Metrics(),
+	})
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UoMHandler handles units of measure and product unit conversion HTTP requests
+type UoMHandler struct {
+	uomUseCase usecase.UoMUseCase
+}
+
+// NewUoMHandler creates a new unit of measure handler
+func NewUoMHandler(uomUseCase usecase.UoMUseCase) *UoMHandler {
+	return &UoMHandler{
+		uomUseCase: uomUseCase,
+	}
+}
+
+// ListUnits handles listing the units of measure master
+func (h *UoMHandler) ListUnits(c *fiber.Ctx) error {
+	units, err := h.uomUseCase.ListUnits(c.Context())
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   units,
+	})
+}
+
+// GetUnit handles getting one unit of measure
+func (h *UoMHandler) GetUnit(c *fiber.Ctx) error {
+	unit, err := h.uomUseCase.GetUnit(c.Context(), c.Params("code"))
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   unit,
+	})
+}
+
+// CreateUnit handles adding a unit of measure
+func (h *UoMHandler) CreateUnit(c *fiber.Ctx) error {
+	var req usecase.CreateUoMRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	unit, err := h.uomUseCase.CreateUnit(c.Context(), &req)
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   unit,
+	})
+}
+
+// UpdateUnit handles editing the name, decimals and rounding rule of a unit
+func (h *UoMHandler) UpdateUnit(c *fiber.Ctx) error {
+	var req usecase.UpdateUoMRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	unit, err := h.uomUseCase.UpdateUnit(c.Context(), c.Params("code"), &req)
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   unit,
+	})
+}
+
+// DeleteUnit handles deleting an unused unit of measure
+func (h *UoMHandler) DeleteUnit(c *fiber.Ctx) error {
+	err := h.uomUseCase.DeleteUnit(c.Context(), c.Params("code"))
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Unit of measure deleted",
+	})
+}
+
+// ListProductUnits handles listing the units a product is handled in
+func (h *UoMHandler) ListProductUnits(c *fiber.Ctx) error {
+	units, err := h.uomUseCase.ListProductUnits(c.Context(), c.Params("id"))
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   units,
+	})
+}
+
+// SetProductUnit handles adding a unit to a product or changing its factor
+func (h *UoMHandler) SetProductUnit(c *fiber.Ctx) error {
+	var req usecase.SetProductUnitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body, factor must be a decimal number",
+		})
+	}
+
+	unit, err := h.uomUseCase.SetProductUnit(c.Context(), c.Params("id"), c.Params("uom"), &req)
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   unit,
+	})
+}
+
+// DeleteProductUnit handles removing a unit from a product
+func (h *UoMHandler) DeleteProductUnit(c *fiber.Ctx) error {
+	err := h.uomUseCase.DeleteProductUnit(c.Context(), c.Params("id"), c.Params("uom"))
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Unit removed from the product",
+	})
+}
+
+// Convert handles converting a quantity to the base unit of a product without changing stock
+func (h *UoMHandler) Convert(c *fiber.Ctx) error {
+	var req usecase.ConvertQuantityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body, quantity must be a decimal number",
+		})
+	}
+
+	quantity, err := h.uomUseCase.Normalize(c.Context(), c.Params("id"), req.Quantity, req.UoM)
+	if err != nil {
+		return uomError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   quantity,
+	})
+}
+
+// uomError maps unit of measure errors to responses
+func uomError(c *fiber.Ctx, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationResponse(c, validationErr)
+	}
+
+	switch err {
+	case domain.ErrUoMNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unit of measure not found",
+		})
+	case domain.ErrProductNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Product not found",
+		})
+	case domain.ErrProductUnitNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unit of measure is not defined for the product",
+		})
+	case domain.ErrUoMAlreadyExists:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unit of measure already exists",
+		})
+	case domain.ErrUoMInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unit of measure is still used by products",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserHandler handles admin user management HTTP requests
+type UserHandler struct {
+	userUseCase usecase.UserUseCase
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userUseCase usecase.UserUseCase) *UserHandler {
+	return &UserHandler{
+		userUseCase: userUseCase,
+	}
+}
+
+// SetUserPasswordRequest represents admin password reset request body
+type SetUserPasswordRequest struct {
+	NewPassword string `json:"new_password"`
+}
+
+// List handles listing users with ?search=, ?role=, ?status=, ?page= and ?page_size=
+func (h *UserHandler) List(c *fiber.Ctx) error {
+	result, err := h.userUseCase.List(c.Context(), &usecase.ListUsersRequest{
+		Search:   c.Query("search"),
+		Role:     domain.UserRole(c.Query("role")),
+		Status:   domain.UserStatus(c.Query("status")),
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size"),
+	})
+	if err != nil {
+		return userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// Get handles getting one user
+func (h *UserHandler) Get(c *fiber.Ctx) error {
+	user, err := h.userUseCase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   user,
+	})
+}
+
+// Update handles editing the fullname and primary role of a user
+func (h *UserHandler) Update(c *fiber.Ctx) error {
+	actor := c.Locals("user").(*domain.User)
+
+	var req usecase.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	user, err := h.userUseCase.Update(c.Context(), actor.ID, c.Params("id"), &req)
+	if err != nil {
+		return userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   user,
+	})
+}
+
+// SetPassword handles setting a new password for a user
+func (h *UserHandler) SetPassword(c *fiber.Ctx) error {
+	var req SetUserPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if req.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "New password is required",
+		})
+	}
+
+	err := h.userUseCase.SetPassword(c.Context(), c.Params("id"), req.NewPassword)
+	if err != nil {
+		return userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Password updated, all sessions of the user have been revoked",
+	})
+}
+
+// Suspend handles temporarily blocking a user
+func (h *UserHandler) Suspend(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.UserStatusSuspended)
+}
+
+// Deactivate handles deactivating a user while keeping its history
+func (h *UserHandler) Deactivate(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.UserStatusDeactivated)
+}
+
+// Activate handles reactivating a suspended or deactivated user
+func (h *UserHandler) Activate(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.UserStatusActive)
+}
+
+func (h *UserHandler) changeStatus(c *fiber.Ctx, status domain.UserStatus) error {
+	actor := c.Locals("user").(*domain.User)
+
+	// The reason is optional, so an empty body is accepted
+	var req usecase.ChangeUserStatusRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid request body",
+			})
+		}
+	}
+	req.Status = status
+
+	user, err := h.userUseCase.ChangeStatus(c.Context(), actor.ID, c.Params("id"), &req)
+	if err != nil {
+		return userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   user,
+	})
+}
+
+// Delete handles deleting a user
+func (h *UserHandler) Delete(c *fiber.Ctx) error {
+	actor := c.Locals("user").(*domain.User)
+
+	err := h.userUseCase.Delete(c.Context(), actor.ID, c.Params("id"))
+	if err != nil {
+		return userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "User deleted",
+	})
+}
+
+// userError maps user management errors to responses
+func userError(c *fiber.Ctx, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationResponse(c, validationErr)
+	}
+
+	switch err {
+	case domain.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	case domain.ErrRoleNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	case domain.ErrCannotManageSelf:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "You cannot remove, demote or deactivate your own account",
+		})
+	case domain.ErrInvalidStatusChange:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Account status cannot be changed to the requested status",
+		})
+	case domain.ErrUserInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User is referenced by stock movements, deactivate it instead",
+		})
+	case domain.ErrBadRequest:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Fullname must not be empty",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
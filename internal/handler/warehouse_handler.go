@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WarehouseHandler handles warehouse and warehouse assignment HTTP requests
+type WarehouseHandler struct {
+	warehouseUseCase usecase.WarehouseUseCase
+}
+
+// NewWarehouseHandler creates a new warehouse handler
+func NewWarehouseHandler(warehouseUseCase usecase.WarehouseUseCase) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseUseCase: warehouseUseCase,
+	}
+}
+
+// List handles listing the warehouses the user may operate with ?search= and ?active=
+func (h *WarehouseHandler) List(c *fiber.Ctx) error {
+	user := c.Locals("user").(*domain.User)
+
+	var active *bool
+	if value := c.Query("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid active, use true or false",
+			})
+		}
+		active = &parsed
+	}
+
+	warehouses, err := h.warehouseUseCase.List(c.Context(), user, &usecase.ListWarehousesRequest{
+		Search: c.Query("search"),
+		Active: active,
+	})
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouses,
+	})
+}
+
+// Get handles getting one warehouse
+func (h *WarehouseHandler) Get(c *fiber.Ctx) error {
+	warehouse, err := h.warehouseUseCase.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouse,
+	})
+}
+
+// Create handles adding a warehouse
+func (h *WarehouseHandler) Create(c *fiber.Ctx) error {
+	var req usecase.CreateWarehouseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	warehouse, err := h.warehouseUseCase.Create(c.Context(), &req)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouse,
+	})
+}
+
+// Update handles editing a warehouse
+func (h *WarehouseHandler) Update(c *fiber.Ctx) error {
+	var req usecase.UpdateWarehouseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	warehouse, err := h.warehouseUseCase.Update(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouse,
+	})
+}
+
+// Delete handles deleting a warehouse
+func (h *WarehouseHandler) Delete(c *fiber.Ctx) error {
+	err := h.warehouseUseCase.Delete(c.Context(), c.Params("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Warehouse deleted",
+	})
+}
+
+// ListUsers handles listing the users assigned to a warehouse
+func (h *WarehouseHandler) ListUsers(c *fiber.Ctx) error {
+	users, err := h.warehouseUseCase.ListUsers(c.Context(), c.Params("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   users,
+	})
+}
+
+// GetUserWarehouses handles listing the warehouses assigned to a user
+func (h *WarehouseHandler) GetUserWarehouses(c *fiber.Ctx) error {
+	warehouses, err := h.warehouseUseCase.GetUserWarehouses(c.Context(), c.Params("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouses,
+	})
+}
+
+// SetUserWarehouses handles replacing the warehouses assigned to a user
+func (h *WarehouseHandler) SetUserWarehouses(c *fiber.Ctx) error {
+	var req usecase.SetUserWarehousesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	warehouses, err := h.warehouseUseCase.SetUserWarehouses(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouses,
+	})
+}
+
+// GetAPIKeyWarehouses handles listing the warehouses assigned to an API key
+func (h *WarehouseHandler) GetAPIKeyWarehouses(c *fiber.Ctx) error {
+	warehouses, err := h.warehouseUseCase.GetAPIKeyWarehouses(c.Context(), c.Params("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouses,
+	})
+}
+
+// SetAPIKeyWarehouses handles replacing the warehouses assigned to an API key
+func (h *WarehouseHandler) SetAPIKeyWarehouses(c *fiber.Ctx) error {
+	var req usecase.SetUserWarehousesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid request body",
+		})
+	}
+
+	warehouses, err := h.warehouseUseCase.SetAPIKeyWarehouses(c.Context(), c.Params("id"), &req)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   warehouses,
+	})
+}
+
+// warehouseError maps warehouse and location errors to responses
+func warehouseError(c *fiber.Ctx, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationResponse(c, validationErr)
+	}
+
+	switch err {
+	case domain.ErrWarehouseNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Warehouse not found",
+		})
+	case domain.ErrLocationNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Location not found",
+		})
+	case domain.ErrUserNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	case domain.ErrAPIKeyNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "API key not found",
+		})
+	case domain.ErrWarehouseCodeUsed:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Warehouse code already used",
+		})
+	case domain.ErrLocationCodeUsed:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Location code already used in the warehouse",
+		})
+	case domain.ErrWarehouseInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Warehouse still holds locations, deactivate it instead",
+		})
+	case domain.ErrLocationInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Location still holds other locations or stock movements, deactivate it instead",
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Internal server error",
+	})
+}
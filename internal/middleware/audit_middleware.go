@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/usecase"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditMiddleware records authenticated requests refused with 403, e.g. by
+// RoleMiddleware, RequirePermission or ApprovalMiddleware, and every request
+// made with an impersonation token. It is installed once for the whole app
+// and inspects the response after the route has run.
+func AuditMiddleware(recorder usecase.AuditRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// The error handler only sets the status once every middleware returned
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		impersonator, impersonating := c.Locals("impersonator").(*domain.User)
+		if status != fiber.StatusForbidden && !impersonating {
+			return err
+		}
+
+		// Refusals before authentication, e.g. of suspended accounts, are recorded by the auth use case
+		user, ok := c.Locals("user").(*domain.User)
+		if !ok {
+			return err
+		}
+
+		// Fiber reuses request buffers, so values are copied before they are kept
+		metadata := map[string]any{
+			"method": strings.Clone(c.Method()),
+			"path":   strings.Clone(c.Path()),
+		}
+
+		event := &domain.AuditEvent{
+			ActorID:       user.ID,
+			ActorUsername: user.Username,
+			Action:        domain.AuditActionAccessDenied,
+			Target:        c.Route().Path,
+			Outcome:       domain.AuditOutcomeDenied,
+			IPAddress:     strings.Clone(c.IP()),
+			UserAgent:     strings.Clone(c.Get(fiber.HeaderUserAgent)),
+			Metadata:      metadata,
+		}
+
+		if status == fiber.StatusForbidden {
+			// Keep the reason and details of the refusal, e.g. the approval limit
+			var body struct {
+				Message string `json:"message"`
+				Data    any    `json:"data"`
+			}
+			if json.Unmarshal(c.Response().Body(), &body) == nil {
+				if body.Message != "" {
+					metadata["reason"] = body.Message
+				}
+				if body.Data != nil {
+					metadata["details"] = body.Data
+				}
+			}
+		}
+
+		// The admin is the actor, the impersonated user is kept in the metadata
+		if impersonating {
+			event.ActorID = impersonator.ID
+			event.ActorUsername = impersonator.Username
+			metadata["impersonated_user_id"] = user.ID
+			metadata["impersonated_username"] = user.Username
+			metadata["status"] = status
+
+			if status != fiber.StatusForbidden {
+				event.Action = domain.AuditActionImpersonationRequest
+				event.Outcome = domain.AuditOutcomeSuccess
+				if status >= fiber.StatusBadRequest {
+					event.Outcome = domain.AuditOutcomeFailure
+				}
+			}
+		}
+
+		recorder.Record(c.Context(), event)
+
+		return err
+	}
+}
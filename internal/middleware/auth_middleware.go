@@ -4,16 +4,39 @@ import (
 	"api-stockflow/internal/domain"
 	"api-stockflow/internal/repository"
 	"api-stockflow/internal/security"
+	"api-stockflow/internal/usecase"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// AuthMiddleware creates authentication middleware
-func AuthMiddleware(tokenManager security.TokenManager, userRepo repository.UserRepository) fiber.Handler {
+// AuthMiddleware creates authentication middleware.
+// Users are served from principals while cached, revoked access tokens are refused.
+// Impersonation tokens authenticate as the impersonated user and set the admin as "impersonator".
+// API keys are accepted as "Authorization: ApiKey <key>" or "X-API-Key: <key>"
+// and act as a principal holding the role and scopes of the key.
+func AuthMiddleware(
+	tokenManager security.TokenManager,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	revocations security.RevocationStore,
+	principals security.PrincipalCache,
+	apiKeys usecase.APIKeyUseCase,
+) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get authorization header
 		authHeader := c.Get("Authorization")
+
+		apiKey := c.Get("X-API-Key")
+		if scheme, key, found := strings.Cut(authHeader, " "); found && scheme == "ApiKey" {
+			apiKey = key
+		}
+		if apiKey != "" {
+			return authenticateAPIKey(c, apiKeys, apiKey)
+		}
+
 		if authHeader == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "fail",
@@ -33,15 +56,14 @@ func AuthMiddleware(tokenManager security.TokenManager, userRepo repository.User
 		// Verify token
 		token := parts[1]
 		payload, err := tokenManager.VerifyAccessToken(token)
-		if err != nil {
+		if err != nil || revocations.IsRevoked(payload) {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "fail",
 				"message": "Invalid or expired token",
 			})
 		}
 
-		// Get user from database
-		user, err := userRepo.GetByID(c.Context(), payload.UserID)
+		user, err := loadPrincipal(c, userRepo, roleRepo, principals, payload.UserID)
 		if err != nil {
 			if err == domain.ErrUserNotFound {
 				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -55,21 +77,110 @@ func AuthMiddleware(tokenManager security.TokenManager, userRepo repository.User
 			})
 		}
 
-		// Set user to context
+		switch user.CheckActive() {
+		case domain.ErrAccountSuspended:
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Account is suspended, please contact Admin",
+			})
+		case domain.ErrAccountDeactivated:
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Account is deactivated",
+			})
+		}
+
+		// The admin must still be allowed to impersonate for the token to stay valid
+		if payload.IsImpersonation() {
+			actor, err := loadPrincipal(c, userRepo, roleRepo, principals, payload.Actor.UserID)
+			if err != nil && err != domain.ErrUserNotFound {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"status":  "error",
+					"message": "Internal server error",
+				})
+			}
+			if err != nil || actor.CheckActive() != nil || !actor.HasPermission(domain.PermissionUserImpersonate) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"status":  "fail",
+					"message": "Impersonation is no longer allowed",
+				})
+			}
+			c.Locals("impersonator", actor)
+		}
+
+		// Set user and session to context
 		c.Locals("user", user)
+		c.Locals("session_id", payload.SessionID)
 
 		return c.Next()
 	}
 }
 
+// authenticateAPIKey sets the principal of an API key in context
+func authenticateAPIKey(c *fiber.Ctx, apiKeys usecase.APIKeyUseCase, apiKey string) error {
+	principal, err := apiKeys.Authenticate(c.Context(), strings.TrimSpace(apiKey))
+	if err != nil {
+		if err == domain.ErrAPIKeyInvalid {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid, expired or revoked API key",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	// API keys have no session
+	c.Locals("user", principal)
+	c.Locals("api_key_id", principal.ID)
+
+	return c.Next()
+}
+
+// loadPrincipal returns the user with roles and permissions, from the cache when possible.
+// Inactive users are returned as well so the caller can reject them with a specific message.
+func loadPrincipal(
+	c *fiber.Ctx,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	principals security.PrincipalCache,
+	userID string,
+) (*domain.User, error) {
+	if user, ok := principals.Get(userID); ok {
+		return user, nil
+	}
+
+	user, err := userRepo.GetByIDIncludingDeactivated(c.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load roles and permissions for authorization checks
+	user.Roles, err = roleRepo.GetUserRoles(c.Context(), user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Permissions, err = roleRepo.GetUserPermissions(c.Context(), user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	principals.Set(user)
+
+	return user, nil
+}
+
 // RoleMiddleware creates role-based authorization middleware
 func RoleMiddleware(allowedRoles ...domain.UserRole) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		user := c.Locals("user").(*domain.User)
 
-		// Check if user role is allowed
+		// Check if any role of the user is allowed
 		for _, role := range allowedRoles {
-			if user.Role == role {
+			if user.HasRole(role) {
 				return c.Next()
 			}
 		}
@@ -81,11 +192,55 @@ func RoleMiddleware(allowedRoles ...domain.UserRole) fiber.Handler {
 	}
 }
 
-// ApprovalMiddleware checks if user can approve based on amount
-func ApprovalMiddleware(amountField string) fiber.Handler {
+// RequirePermission creates permission-based authorization middleware.
+// The user must hold every listed permission through one of their roles.
+func RequirePermission(permissions ...domain.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := c.Locals("user").(*domain.User)
+
+		for _, permission := range permissions {
+			if !user.HasPermission(permission) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"status":  "fail",
+					"message": "Insufficient permissions",
+				})
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// DenyImpersonation refuses impersonation tokens, for routes only the user in person may use
+func DenyImpersonation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, impersonating := c.Locals("impersonator").(*domain.User); impersonating {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Not allowed while impersonating a user",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// ApprovalMiddleware checks the amount in the request body against the
+// approval limit of the user for documentType. The optional "currency" body
+// field selects the currency limit. The decision is stored in the context
+// as "approval" for the handler. Impersonation tokens are always refused.
+func ApprovalMiddleware(approvalUseCase usecase.ApprovalUseCase, documentType, amountField string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		user := c.Locals("user").(*domain.User)
 
+		// Approvals are made by the approver in person, never on their behalf
+		if _, impersonating := c.Locals("impersonator").(*domain.User); impersonating {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Approvals are not allowed while impersonating a user",
+			})
+		}
+
 		// Parse request body to get amount
 		var body map[string]interface{}
 		if err := c.BodyParser(&body); err != nil {
@@ -104,14 +259,125 @@ func ApprovalMiddleware(amountField string) fiber.Handler {
 			})
 		}
 
+		currency, _ := body["currency"].(string)
+
+		decision, err := approvalUseCase.Decide(c.Context(), user, &usecase.ApprovalRequest{
+			Amount:       amount,
+			Currency:     currency,
+			DocumentType: documentType,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Internal server error",
+			})
+		}
+
 		// Check if user can approve
-		if !user.CanApprove(amount) {
+		if !decision.Allowed {
+			message := "You are not allowed to approve this document. Please contact Admin/Manager"
+			if limit := decision.MaxAmount(); limit != nil {
+				message = fmt.Sprintf("You cannot approve amounts over %s %s. Please contact Admin/Manager",
+					decision.Currency, formatAmount(*limit))
+			}
+
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": message,
+				"data":    decision,
+			})
+		}
+
+		c.Locals("approval", decision)
+
+		return c.Next()
+	}
+}
+
+// formatAmount formats an amount with thousands separators, e.g. 10,000,000
+func formatAmount(amount float64) string {
+	text := strconv.FormatFloat(amount, 'f', -1, 64)
+
+	integer, fraction, _ := strings.Cut(text, ".")
+	var b strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		b.WriteString("." + fraction)
+	}
+	return b.String()
+}
+
+// WarehouseSource tells WarehouseScope where to find the warehouse of a request
+type WarehouseSource int
+
+const (
+	// WarehouseFromParam reads the warehouse ID from a route parameter
+	WarehouseFromParam WarehouseSource = iota
+	// WarehouseFromQuery reads the warehouse ID from a query parameter, requests without it pass
+	WarehouseFromQuery
+	// LocationFromParam reads a location ID from a route parameter and checks its warehouse
+	LocationFromParam
+)
+
+// WarehouseScope creates warehouse authorization middleware. It refuses users
+// who are not assigned to the warehouse named by key, read from source, unless
+// they hold warehouse:manage. The warehouse ID is stored in the context as
+// "warehouse_id" for the handler. Use it after RoleMiddleware or
+// RequirePermission, which decide what a user may do, to limit where.
+func WarehouseScope(warehouseUseCase usecase.WarehouseUseCase, source WarehouseSource, key string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := c.Locals("user").(*domain.User)
+
+		var warehouseID string
+		switch source {
+		case WarehouseFromParam:
+			warehouseID = c.Params(key)
+		case WarehouseFromQuery:
+			warehouseID = c.Query(key)
+			if warehouseID == "" {
+				return c.Next()
+			}
+		case LocationFromParam:
+			var err error
+			warehouseID, err = warehouseUseCase.LocationWarehouse(c.Context(), c.Params(key))
+			if err == domain.ErrLocationNotFound {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"status":  "fail",
+					"message": "Location not found",
+				})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"status":  "error",
+					"message": "Internal server error",
+				})
+			}
+		}
+
+		allowed, err := warehouseUseCase.CanOperate(c.Context(), user, warehouseID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Internal server error",
+			})
+		}
+
+		if !allowed {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"status":  "fail",
-				"message": "You cannot approve purchases over 10,000,000. Please contact Admin/Manager",
+				"message": "You are not assigned to this warehouse",
 			})
 		}
 
+		// Fiber reuses request buffers, so the ID is copied before it is kept
+		c.Locals("warehouse_id", strings.Clone(warehouseID))
+
 		return c.Next()
 	}
 }
@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+type fileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// fileNotification is one line of the notification file
+type fileNotification struct {
+	Type      string    `json:"type"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Link      string    `json:"link"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewFileNotifier creates a notifier that appends notifications as JSON lines to path
+func NewFileNotifier(path string) Notifier {
+	return &fileNotifier{path: path}
+}
+
+func (n *fileNotifier) SendPasswordReset(ctx context.Context, user *domain.User, link string, expiresAt time.Time) error {
+	return n.write(fileNotification{
+		Type:      "password_reset",
+		UserID:    user.ID,
+		Username:  user.Username,
+		Link:      link,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (n *fileNotifier) write(notification fileNotification) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
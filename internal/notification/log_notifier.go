@@ -0,0 +1,21 @@
+package notification
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"log"
+	"time"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier creates a notifier that writes notifications to the application log.
+// It is intended for local development only.
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) SendPasswordReset(ctx context.Context, user *domain.User, link string, expiresAt time.Time) error {
+	log.Printf("Password reset for user '%s' (valid until %s): %s\n", user.Username, expiresAt.Format(time.RFC3339), link)
+	return nil
+}
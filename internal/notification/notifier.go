@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"os"
+	"time"
+)
+
+// Notifier delivers account notifications to users
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, user *domain.User, link string, expiresAt time.Time) error
+}
+
+// NewNotifier creates the notifier selected by NOTIFIER_DRIVER.
+// Supported drivers are "log" (default) and "file".
+func NewNotifier() Notifier {
+	switch os.Getenv("NOTIFIER_DRIVER") {
+	case "file":
+		path := os.Getenv("NOTIFIER_FILE_PATH")
+		if path == "" {
+			path = "notifications.log"
+		}
+		return NewFileNotifier(path)
+	default:
+		return NewLogNotifier()
+	}
+}
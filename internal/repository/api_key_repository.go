@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"time"
+)
+
+// APIKeyRepository defines the interface for API key data operations
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	List(ctx context.Context) ([]*domain.APIKey, error)
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+const apiKeySelect = `
+	SELECT k.id, k.name, k.prefix, k.key_hash, k.role, COALESCE(k.created_by, ''),
+		k.expires_at, k.last_used_at, k.revoked_at, k.created_at,
+		COALESCE(STRING_AGG(s.permission, ',' ORDER BY s.permission), '')
+	FROM api_keys k
+	LEFT JOIN api_key_scopes s ON s.api_key_id = k.id
+`
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, role, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		key.ID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.Role,
+		key.CreatedBy,
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrRoleNotFound
+		}
+		return err
+	}
+
+	for _, scope := range key.Scopes {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO api_key_scopes (api_key_id, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			key.ID, scope,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return domain.ErrPermissionNotFound
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	query := apiKeySelect + `
+		GROUP BY k.id
+		ORDER BY k.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	query := apiKeySelect + `
+		WHERE k.id = $1
+		GROUP BY k.id
+	`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	query := apiKeySelect + `
+		WHERE k.prefix = $1
+		GROUP BY k.id
+	`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, prefix))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Revoke keeps the key for history, it can no longer authenticate
+func (r *apiKeyRepository) Revoke(ctx context.Context, id string) error {
+	query := `UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, usedAt)
+	return err
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+	var scopes string
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Role,
+		&key.CreatedBy,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+		&scopes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scopes are aggregated as a comma separated list
+	key.Scopes = []domain.Permission{}
+	if scopes != "" {
+		for _, scope := range strings.Split(scopes, ",") {
+			key.Scopes = append(key.Scopes, domain.Permission(scope))
+		}
+	}
+
+	return key, nil
+}
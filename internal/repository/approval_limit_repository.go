@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// ApprovalLimitFilter narrows the approval limits returned by List
+type ApprovalLimitFilter struct {
+	Role   domain.UserRole
+	UserID string
+}
+
+// ApprovalLimitRepository defines the interface for approval limit data operations
+type ApprovalLimitRepository interface {
+	List(ctx context.Context, filter ApprovalLimitFilter) ([]*domain.ApprovalLimit, error)
+	GetByID(ctx context.Context, id string) (*domain.ApprovalLimit, error)
+	Create(ctx context.Context, limit *domain.ApprovalLimit) error
+	Update(ctx context.Context, limit *domain.ApprovalLimit) error
+	Delete(ctx context.Context, id string) error
+	// FindApplicable returns the limits of the user and of its roles matching
+	// the currency and document type, including the catch-all limits
+	FindApplicable(ctx context.Context, userID string, roles []domain.UserRole, currency, documentType string) ([]*domain.ApprovalLimit, error)
+}
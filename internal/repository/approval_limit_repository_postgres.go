@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type approvalLimitRepository struct {
+	db *sql.DB
+}
+
+// NewApprovalLimitRepository creates a new approval limit repository
+func NewApprovalLimitRepository(db *sql.DB) ApprovalLimitRepository {
+	return &approvalLimitRepository{db: db}
+}
+
+const approvalLimitColumns = `
+	id, COALESCE(role, ''), COALESCE(user_id, ''), currency, document_type,
+	max_amount::FLOAT8, created_at, updated_at
+`
+
+func (r *approvalLimitRepository) List(ctx context.Context, filter ApprovalLimitFilter) ([]*domain.ApprovalLimit, error) {
+	query := `
+		SELECT ` + approvalLimitColumns + `
+		FROM approval_limits
+		WHERE ($1 = '' OR role = $1) AND ($2 = '' OR user_id = $2)
+		ORDER BY role NULLS LAST, user_id, currency, document_type
+	`
+
+	return r.query(ctx, query, string(filter.Role), filter.UserID)
+}
+
+func (r *approvalLimitRepository) GetByID(ctx context.Context, id string) (*domain.ApprovalLimit, error) {
+	query := `SELECT ` + approvalLimitColumns + ` FROM approval_limits WHERE id = $1`
+
+	limit, err := scanApprovalLimit(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrApprovalLimitNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+func (r *approvalLimitRepository) Create(ctx context.Context, limit *domain.ApprovalLimit) error {
+	query := `
+		INSERT INTO approval_limits (id, role, user_id, currency, document_type, max_amount, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		limit.ID,
+		string(limit.Role),
+		limit.UserID,
+		limit.Currency,
+		limit.DocumentType,
+		nullAmount(limit.MaxAmount),
+		limit.CreatedAt,
+		limit.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrApprovalLimitExists
+		}
+		if isForeignKeyViolation(err) {
+			if limit.UserID != "" {
+				return domain.ErrUserNotFound
+			}
+			return domain.ErrRoleNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Update changes the amount of a limit; its role or user and scope are fixed
+func (r *approvalLimitRepository) Update(ctx context.Context, limit *domain.ApprovalLimit) error {
+	query := `UPDATE approval_limits SET max_amount = $2, updated_at = $3 WHERE id = $1`
+
+	limit.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, query, limit.ID, nullAmount(limit.MaxAmount), limit.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrApprovalLimitNotFound
+	}
+
+	return nil
+}
+
+func (r *approvalLimitRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM approval_limits WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrApprovalLimitNotFound
+	}
+
+	return nil
+}
+
+func (r *approvalLimitRepository) FindApplicable(ctx context.Context, userID string, roles []domain.UserRole, currency, documentType string) ([]*domain.ApprovalLimit, error) {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+
+	// Roles are passed as a comma separated list since database/sql cannot bind slices
+	query := `
+		SELECT ` + approvalLimitColumns + `
+		FROM approval_limits
+		WHERE (user_id = $1 OR role = ANY(STRING_TO_ARRAY(NULLIF($2, ''), ',')))
+			AND currency IN ('', $3)
+			AND document_type IN ('', $4)
+	`
+
+	return r.query(ctx, query, userID, strings.Join(names, ","), currency, documentType)
+}
+
+func (r *approvalLimitRepository) query(ctx context.Context, query string, args ...any) ([]*domain.ApprovalLimit, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	limits := []*domain.ApprovalLimit{}
+	for rows.Next() {
+		limit, err := scanApprovalLimit(rows)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func scanApprovalLimit(row rowScanner) (*domain.ApprovalLimit, error) {
+	limit := &domain.ApprovalLimit{}
+	var role string
+	var maxAmount sql.NullFloat64
+	err := row.Scan(
+		&limit.ID,
+		&role,
+		&limit.UserID,
+		&limit.Currency,
+		&limit.DocumentType,
+		&maxAmount,
+		&limit.CreatedAt,
+		&limit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	limit.Role = domain.UserRole(role)
+	if maxAmount.Valid {
+		limit.MaxAmount = &maxAmount.Float64
+	}
+
+	return limit, nil
+}
+
+// nullAmount converts an optional amount to a nullable SQL value
+func nullAmount(amount *float64) sql.NullFloat64 {
+	if amount == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *amount, Valid: true}
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"time"
+)
+
+// AuditFilter narrows and pages the audit events returned by List
+type AuditFilter struct {
+	// Actor matches the actor ID or username exactly
+	Actor   string
+	Action  domain.AuditAction
+	Outcome domain.AuditOutcome
+	// From and To bound the creation time, unbounded when zero
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// AuditRepository defines the interface for audit log data operations.
+// Events can only be added, never changed or removed.
+type AuditRepository interface {
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	// List returns one page of events, newest first, and the total number of matching events
+	List(ctx context.Context, filter AuditFilter) ([]*domain.AuditEvent, int, error)
+	// ForEach calls fn for every matching event, oldest first, ignoring Limit and Offset
+	ForEach(ctx context.Context, filter AuditFilter, fn func(event *domain.AuditEvent) error) error
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+const auditColumns = `
+	id, COALESCE(actor_id, ''), COALESCE(actor_username, ''), action, COALESCE(target, ''),
+	outcome, COALESCE(ip_address, ''), COALESCE(user_agent, ''), metadata, created_at
+`
+
+// auditWhere filters by the first five query arguments, see auditArgs
+const auditWhere = `
+	WHERE ($1 = '' OR actor_id = $1 OR actor_username = $1)
+		AND ($2 = '' OR action = $2)
+		AND ($3 = '' OR outcome = $3)
+		AND ($4::timestamptz IS NULL OR created_at >= $4)
+		AND ($5::timestamptz IS NULL OR created_at < $5)
+`
+
+func auditArgs(filter AuditFilter) []any {
+	var from, to sql.NullTime
+	if !filter.From.IsZero() {
+		from = sql.NullTime{Time: filter.From, Valid: true}
+	}
+	if !filter.To.IsZero() {
+		to = sql.NullTime{Time: filter.To, Valid: true}
+	}
+
+	return []any{filter.Actor, filter.Action, filter.Outcome, from, to}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event *domain.AuditEvent) error {
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+	if event.Metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO audit_events (
+			id, actor_id, actor_username, action, target, outcome, ip_address, user_agent, metadata, created_at
+		)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, NULLIF($5, ''), $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		event.ID,
+		event.ActorID,
+		event.ActorUsername,
+		event.Action,
+		event.Target,
+		event.Outcome,
+		event.IPAddress,
+		event.UserAgent,
+		metadata,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *auditRepository) List(ctx context.Context, filter AuditFilter) ([]*domain.AuditEvent, int, error) {
+	args := auditArgs(filter)
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_events`+auditWhere, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_events` + auditWhere + `
+		ORDER BY created_at DESC, id
+		LIMIT $6 OFFSET $7
+	`
+
+	events := []*domain.AuditEvent{}
+	err = r.query(ctx, query, append(args, filter.Limit, filter.Offset), func(event *domain.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+func (r *auditRepository) ForEach(ctx context.Context, filter AuditFilter, fn func(event *domain.AuditEvent) error) error {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_events` + auditWhere + `
+		ORDER BY created_at, id
+	`
+
+	return r.query(ctx, query, auditArgs(filter), fn)
+}
+
+func (r *auditRepository) query(ctx context.Context, query string, args []any, fn func(event *domain.AuditEvent) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event := &domain.AuditEvent{}
+		var metadata []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.ActorID,
+			&event.ActorUsername,
+			&event.Action,
+			&event.Target,
+			&event.Outcome,
+			&event.IPAddress,
+			&event.UserAgent,
+			&metadata,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+			return err
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -1,12 +1,29 @@
 package repository
 
 import (
+	"api-stockflow/internal/domain"
 	"context"
+	"time"
 )
 
-// AuthenticationRepository defines the interface for authentication data operations
+// AuthenticationRepository defines the interface for authentication data operations.
+// Refresh tokens are identified by their hash, see security.HashToken.
 type AuthenticationRepository interface {
-	AddToken(ctx context.Context, token string, userID string) error
-	CheckTokenAvailability(ctx context.Context, token string) error
-	DeleteToken(ctx context.Context, token string) error
+	AddToken(ctx context.Context, auth *domain.Authentication) error
+	GetToken(ctx context.Context, tokenHash string) (*domain.Authentication, error)
+	CheckTokenAvailability(ctx context.Context, tokenHash string) error
+	RotateToken(ctx context.Context, oldTokenHash string, newAuth *domain.Authentication) error
+	DeleteToken(ctx context.Context, tokenHash string) error
+	DeleteTokenFamily(ctx context.Context, familyID string) error
+	DeleteTokensByUserID(ctx context.Context, userID string) error
+	ListSessions(ctx context.Context, userID string) ([]*domain.Session, error)
+	DeleteSession(ctx context.Context, userID string, sessionID string) error
+	DeleteOtherSessions(ctx context.Context, userID string, currentSessionID string) (int64, error)
+	DeleteStaleTokens(ctx context.Context, now time.Time, limit int) (*StaleTokenCount, error)
+}
+
+// StaleTokenCount is the number of tokens removed by a cleanup batch
+type StaleTokenCount struct {
+	Expired int64
+	Revoked int64
 }
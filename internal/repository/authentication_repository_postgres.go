@@ -16,21 +16,63 @@ func NewAuthenticationRepository(db *sql.DB) AuthenticationRepository {
 	return &authenticationRepository{db: db}
 }
 
-func (r *authenticationRepository) AddToken(ctx context.Context, token string, userID string) error {
+func (r *authenticationRepository) AddToken(ctx context.Context, auth *domain.Authentication) error {
 	query := `
-		INSERT INTO authentications (token, user_id, created_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO authentications (token_hash, user_id, family_id, parent_token_hash, user_agent, ip_address, expires_at, last_used_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $8)
 	`
 
-	_, err := r.db.ExecContext(ctx, query, token, userID, time.Now())
+	_, err := r.db.ExecContext(ctx, query,
+		auth.TokenHash,
+		auth.UserID,
+		auth.FamilyID,
+		auth.ParentTokenHash,
+		auth.UserAgent,
+		auth.IPAddress,
+		auth.ExpiresAt,
+		time.Now(),
+	)
 	return err
 }
 
-func (r *authenticationRepository) CheckTokenAvailability(ctx context.Context, token string) error {
-	query := `SELECT token FROM authentications WHERE token = $1`
+func (r *authenticationRepository) GetToken(ctx context.Context, tokenHash string) (*domain.Authentication, error) {
+	query := `
+		SELECT token_hash, user_id, family_id, COALESCE(parent_token_hash, ''), user_agent, ip_address,
+			revoked_at, expires_at, last_used_at, created_at
+		FROM authentications
+		WHERE token_hash = $1
+	`
+
+	auth := &domain.Authentication{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&auth.TokenHash,
+		&auth.UserID,
+		&auth.FamilyID,
+		&auth.ParentTokenHash,
+		&auth.UserAgent,
+		&auth.IPAddress,
+		&auth.RevokedAt,
+		&auth.ExpiresAt,
+		&auth.LastUsedAt,
+		&auth.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefreshTokenNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+func (r *authenticationRepository) CheckTokenAvailability(ctx context.Context, tokenHash string) error {
+	query := `SELECT token_hash FROM authentications WHERE token_hash = $1 AND revoked_at IS NULL`
 
-	var storedToken string
-	err := r.db.QueryRowContext(ctx, query, token).Scan(&storedToken)
+	var storedHash string
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&storedHash)
 
 	if err == sql.ErrNoRows {
 		return domain.ErrRefreshTokenNotFound
@@ -43,10 +85,59 @@ func (r *authenticationRepository) CheckTokenAvailability(ctx context.Context, t
 	return nil
 }
 
-func (r *authenticationRepository) DeleteToken(ctx context.Context, token string) error {
-	query := `DELETE FROM authentications WHERE token = $1`
+// RotateToken retires oldTokenHash and stores newAuth in a single transaction.
+// It returns domain.ErrRefreshTokenReused when the old token was already retired,
+// which also covers two concurrent refreshes racing on the same token.
+func (r *authenticationRepository) RotateToken(ctx context.Context, oldTokenHash string, newAuth *domain.Authentication) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.ExecContext(ctx, query, token)
+	result, err := tx.ExecContext(ctx,
+		`UPDATE authentications SET revoked_at = $2 WHERE token_hash = $1 AND revoked_at IS NULL`,
+		oldTokenHash, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	query := `
+		INSERT INTO authentications (token_hash, user_id, family_id, parent_token_hash, user_agent, ip_address, expires_at, last_used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		newAuth.TokenHash,
+		newAuth.UserID,
+		newAuth.FamilyID,
+		oldTokenHash,
+		newAuth.UserAgent,
+		newAuth.IPAddress,
+		newAuth.ExpiresAt,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *authenticationRepository) DeleteToken(ctx context.Context, tokenHash string) error {
+	query := `DELETE FROM authentications WHERE token_hash = $1`
+
+	result, err := r.db.ExecContext(ctx, query, tokenHash)
 	if err != nil {
 		return err
 	}
@@ -62,3 +153,132 @@ func (r *authenticationRepository) DeleteToken(ctx context.Context, token string
 
 	return nil
 }
+
+func (r *authenticationRepository) DeleteTokenFamily(ctx context.Context, familyID string) error {
+	query := `DELETE FROM authentications WHERE family_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return err
+}
+
+func (r *authenticationRepository) DeleteTokensByUserID(ctx context.Context, userID string) error {
+	query := `DELETE FROM authentications WHERE user_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
+
+// ListSessions returns one entry per token family that still has an active, unexpired token.
+// The session start time is taken from the first token of the family.
+func (r *authenticationRepository) ListSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	query := `
+		SELECT a.family_id, a.user_agent, a.ip_address, a.last_used_at,
+			(SELECT MIN(f.created_at) FROM authentications f WHERE f.family_id = a.family_id)
+		FROM authentications a
+		WHERE a.user_id = $1 AND a.revoked_at IS NULL AND a.expires_at > NOW()
+		ORDER BY a.last_used_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*domain.Session{}
+	for rows.Next() {
+		session := &domain.Session{}
+		err := rows.Scan(
+			&session.ID,
+			&session.UserAgent,
+			&session.IPAddress,
+			&session.LastUsedAt,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *authenticationRepository) DeleteSession(ctx context.Context, userID string, sessionID string) error {
+	query := `DELETE FROM authentications WHERE user_id = $1 AND family_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// DeleteOtherSessions deletes every session of the user except currentSessionID
+// and returns the number of sessions removed
+func (r *authenticationRepository) DeleteOtherSessions(ctx context.Context, userID string, currentSessionID string) (int64, error) {
+	query := `
+		WITH deleted AS (
+			DELETE FROM authentications
+			WHERE user_id = $1 AND family_id <> $2
+			RETURNING family_id
+		)
+		SELECT COUNT(DISTINCT family_id) FROM deleted
+	`
+
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, userID, currentSessionID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteStaleTokens deletes up to limit tokens that expired before now, and
+// retired tokens of sessions without a valid token left. Retired tokens of
+// live sessions are kept because they are needed to detect reuse.
+// Rows locked by another instance running the cleanup are skipped.
+func (r *authenticationRepository) DeleteStaleTokens(ctx context.Context, now time.Time, limit int) (*StaleTokenCount, error) {
+	query := `
+		WITH stale AS (
+			SELECT a.token_hash, a.expires_at <= $1 AS expired
+			FROM authentications a
+			WHERE a.expires_at <= $1
+				OR (a.revoked_at IS NOT NULL AND NOT EXISTS (
+					SELECT 1 FROM authentications f
+					WHERE f.family_id = a.family_id AND f.revoked_at IS NULL AND f.expires_at > $1
+				))
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), deleted AS (
+			DELETE FROM authentications a
+			USING stale s
+			WHERE a.token_hash = s.token_hash
+			RETURNING s.expired
+		)
+		SELECT COUNT(*) FILTER (WHERE expired), COUNT(*) FILTER (WHERE NOT expired) FROM deleted
+	`
+
+	count := &StaleTokenCount{}
+	err := r.db.QueryRowContext(ctx, query, now, limit).Scan(&count.Expired, &count.Revoked)
+	if err != nil {
+		return nil, err
+	}
+
+	return count, nil
+}
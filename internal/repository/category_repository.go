@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// CategoryRepository defines the interface for category tree data operations.
+// Categories are returned with their path and depth, ordered depth-first by name.
+type CategoryRepository interface {
+	Create(ctx context.Context, category *domain.Category) error
+	GetByID(ctx context.Context, id string) (*domain.Category, error)
+	// List returns the subtree of rootID including rootID itself, the whole tree when rootID is empty
+	List(ctx context.Context, rootID string) ([]*domain.Category, error)
+	// Update stores the name and description, use Move to change the parent
+	Update(ctx context.Context, category *domain.Category) error
+	// Move places the category below parentID, or at the top level when parentID is empty
+	Move(ctx context.Context, id, parentID string) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type categoryRepository struct {
+	db *sql.DB
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *sql.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	query := `
+		INSERT INTO categories (id, parent_id, name, description, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		category.ID,
+		category.ParentID,
+		category.Name,
+		category.Description,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+
+	return categoryWriteError(err)
+}
+
+// categoryTree walks the tree from the top-level categories, collecting the
+// ids and names of the ancestors of every category along the way
+const categoryTree = `
+	WITH RECURSIVE tree AS (
+		SELECT id, parent_id, name, description, created_at, updated_at,
+			ARRAY[id]::TEXT[] AS ids, ARRAY[name]::TEXT[] AS path, ARRAY[LOWER(name)]::TEXT[] AS sort_key
+		FROM categories
+		WHERE parent_id IS NULL
+		UNION ALL
+		SELECT c.id, c.parent_id, c.name, c.description, c.created_at, c.updated_at,
+			t.ids || c.id::TEXT, t.path || c.name::TEXT, t.sort_key || LOWER(c.name)
+		FROM categories c
+		JOIN tree t ON c.parent_id = t.id
+	)
+	SELECT id, COALESCE(parent_id, ''), name, description, to_json(path), CARDINALITY(ids) - 1, created_at, updated_at
+	FROM tree
+`
+
+func (r *categoryRepository) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+	category, err := scanCategory(r.db.QueryRowContext(ctx, categoryTree+` WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (r *categoryRepository) List(ctx context.Context, rootID string) ([]*domain.Category, error) {
+	query := categoryTree + `
+		WHERE $1 = '' OR $1 = ANY(ids)
+		ORDER BY sort_key
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*domain.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if rootID != "" && len(categories) == 0 {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	query := `
+		UPDATE categories
+		SET name = $2, description = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, category.ID, category.Name, category.Description, category.UpdatedAt)
+	if err != nil {
+		return categoryWriteError(err)
+	}
+
+	return checkAffected(result, domain.ErrCategoryNotFound)
+}
+
+func (r *categoryRepository) Move(ctx context.Context, id, parentID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Two concurrent moves could each pass the cycle check and still form a cycle together.
+	// The lock serializes moves while reads and other writes go on.
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	if parentID != "" {
+		// The category must not be the new parent or one of its ancestors
+		query := `
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM categories WHERE id = $1
+				UNION ALL
+				SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)
+		`
+
+		var cycle bool
+		if err := tx.QueryRowContext(ctx, query, parentID, id).Scan(&cycle); err != nil {
+			return err
+		}
+		if cycle {
+			return domain.ErrCategoryCycle
+		}
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE categories SET parent_id = NULLIF($2, ''), updated_at = $3 WHERE id = $1`,
+		id, parentID, time.Now(),
+	)
+	if err != nil {
+		return categoryWriteError(err)
+	}
+
+	if err := checkAffected(result, domain.ErrCategoryNotFound); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrCategoryInUse
+		}
+		return err
+	}
+
+	return checkAffected(result, domain.ErrCategoryNotFound)
+}
+
+// categoryWriteError maps constraint violations of category writes to domain errors
+func categoryWriteError(err error) error {
+	if isUniqueViolation(err) {
+		return domain.ErrCategoryAlreadyExists
+	}
+	// The parent does not exist
+	if isForeignKeyViolation(err) {
+		return domain.ErrCategoryNotFound
+	}
+	return err
+}
+
+func scanCategory(row rowScanner) (*domain.Category, error) {
+	category := &domain.Category{}
+	var path []byte
+	err := row.Scan(
+		&category.ID,
+		&category.ParentID,
+		&category.Name,
+		&category.Description,
+		&path,
+		&category.Depth,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(path, &category.Path); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// InvitationRepository defines the interface for invitation data operations
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) error
+	List(ctx context.Context, pendingOnly bool) ([]*domain.Invitation, error)
+	Delete(ctx context.Context, id string) error
+	// Consume marks a pending invitation as used by userID and returns it
+	Consume(ctx context.Context, tokenHash, userID string) (*domain.Invitation, error)
+	// Release makes a consumed invitation usable again, e.g. when registration failed
+	Release(ctx context.Context, id string) error
+}
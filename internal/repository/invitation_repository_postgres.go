@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type invitationRepository struct {
+	db *sql.DB
+}
+
+// NewInvitationRepository creates a new invitation repository
+func NewInvitationRepository(db *sql.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+const invitationColumns = `
+	id, role, token_hash, COALESCE(created_by, ''), expires_at, used_at,
+	COALESCE(used_by, ''), created_at
+`
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	query := `
+		INSERT INTO invitations (id, role, token_hash, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		invitation.ID,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.CreatedBy,
+		invitation.ExpiresAt,
+		invitation.CreatedAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrRoleNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *invitationRepository) List(ctx context.Context, pendingOnly bool) ([]*domain.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM invitations
+		WHERE NOT $1 OR (used_at IS NULL AND expires_at > $2)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pendingOnly, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*domain.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM invitations WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// Consume checks and marks the invitation in one statement so it can only be used once
+func (r *invitationRepository) Consume(ctx context.Context, tokenHash, userID string) (*domain.Invitation, error) {
+	query := `
+		UPDATE invitations
+		SET used_at = $3, used_by = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $3
+		RETURNING ` + invitationColumns
+
+	invitation, err := scanInvitation(r.db.QueryRowContext(ctx, query, tokenHash, userID, time.Now()))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvitationInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (r *invitationRepository) Release(ctx context.Context, id string) error {
+	query := `UPDATE invitations SET used_at = NULL, used_by = NULL WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	invitation := &domain.Invitation{}
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitation.CreatedBy,
+		&invitation.ExpiresAt,
+		&invitation.UsedAt,
+		&invitation.UsedBy,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// LocationFilter narrows and pages the locations returned by List
+type LocationFilter struct {
+	// Search matches part of the code or name, case-insensitive
+	Search      string
+	WarehouseID string
+	ParentID    string
+	Level       domain.LocationLevel
+	Type        domain.LocationType
+	// Active matches active or inactive locations; every location when nil
+	Active *bool
+	// UserID limits the locations to the active warehouses assigned to the user when set
+	UserID string
+	Limit  int
+	Offset int
+}
+
+// LocationRepository defines the interface for warehouse location data operations
+type LocationRepository interface {
+	Create(ctx context.Context, location *domain.Location) error
+	GetByID(ctx context.Context, id string) (*domain.Location, error)
+	// List returns one page of locations ordered by warehouse and code, and the total number of matching locations
+	List(ctx context.Context, filter LocationFilter) ([]*domain.Location, int, error)
+	Update(ctx context.Context, location *domain.Location) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+)
+
+type locationRepository struct {
+	db *sql.DB
+}
+
+// NewLocationRepository creates a new location repository
+func NewLocationRepository(db *sql.DB) LocationRepository {
+	return &locationRepository{db: db}
+}
+
+func (r *locationRepository) Create(ctx context.Context, location *domain.Location) error {
+	query := `
+		INSERT INTO locations (id, warehouse_id, parent_id, code, name, level, type, capacity, active, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		location.ID,
+		location.WarehouseID,
+		location.ParentID,
+		location.Code,
+		location.Name,
+		location.Level,
+		location.Type,
+		location.Capacity,
+		location.Active,
+		location.CreatedAt,
+		location.UpdatedAt,
+	)
+
+	return locationWriteError(err)
+}
+
+// locationColumns are the columns read by scanLocation
+const locationColumns = `
+	id, warehouse_id, COALESCE(parent_id, ''), code, name, level, type, capacity, active, created_at, updated_at
+`
+
+func (r *locationRepository) GetByID(ctx context.Context, id string) (*domain.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM locations WHERE id = $1`
+
+	location, err := scanLocation(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrLocationNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (r *locationRepository) List(ctx context.Context, filter LocationFilter) ([]*domain.Location, int, error) {
+	where := `
+		WHERE ($1 = '' OR code ILIKE '%' || $1 || '%' OR name ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR warehouse_id = $2)
+			AND ($3 = '' OR parent_id = $3)
+			AND ($4 = '' OR level = $4)
+			AND ($5 = '' OR type = $5)
+			AND ($6::BOOLEAN IS NULL OR active = $6)
+			AND ($7 = '' OR warehouse_id IN (` + operableWarehouses("$7") + `))
+	`
+	args := []any{filter.Search, filter.WarehouseID, filter.ParentID, filter.Level, filter.Type, filter.Active, filter.UserID}
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM locations`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + locationColumns + `
+		FROM locations` + where + `
+		ORDER BY warehouse_id, code
+		LIMIT $8 OFFSET $9
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	locations := []*domain.Location{}
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		locations = append(locations, location)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return locations, total, nil
+}
+
+// Update stores everything but the warehouse, parent and level, which are fixed at creation
+func (r *locationRepository) Update(ctx context.Context, location *domain.Location) error {
+	query := `
+		UPDATE locations
+		SET code = $2, name = $3, type = $4, capacity = $5, active = $6, updated_at = $7
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		location.ID,
+		location.Code,
+		location.Name,
+		location.Type,
+		location.Capacity,
+		location.Active,
+		location.UpdatedAt,
+	)
+	if err != nil {
+		return locationWriteError(err)
+	}
+
+	return checkAffected(result, domain.ErrLocationNotFound)
+}
+
+func (r *locationRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM locations WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrLocationInUse
+		}
+		return err
+	}
+
+	return checkAffected(result, domain.ErrLocationNotFound)
+}
+
+// locationWriteError maps constraint violations of location writes to domain errors
+func locationWriteError(err error) error {
+	if isUniqueViolation(err) {
+		return domain.ErrLocationCodeUsed
+	}
+	// The warehouse or parent was deleted in the meantime
+	if isForeignKeyViolation(err) {
+		if violatedConstraint(err) == "locations_warehouse_id_fkey" {
+			return domain.ErrWarehouseNotFound
+		}
+		return domain.ErrLocationNotFound
+	}
+	return err
+}
+
+func scanLocation(row rowScanner) (*domain.Location, error) {
+	location := &domain.Location{}
+	var capacity *domain.Decimal
+	err := row.Scan(
+		&location.ID,
+		&location.WarehouseID,
+		&location.ParentID,
+		&location.Code,
+		&location.Name,
+		&location.Level,
+		&location.Type,
+		&capacity,
+		&location.Active,
+		&location.CreatedAt,
+		&location.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	location.Capacity = capacity
+	return location, nil
+}
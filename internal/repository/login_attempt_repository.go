@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"time"
+)
+
+// LoginAttemptRepository defines the interface for login attempt data operations
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (*domain.LoginAttempt, error)
+	RecordFailure(ctx context.Context, key string, window time.Duration, lockoutReset time.Duration) (*domain.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
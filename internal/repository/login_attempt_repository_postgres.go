@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type loginAttemptRepository struct {
+	db *sql.DB
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository
+func NewLoginAttemptRepository(db *sql.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	query := `
+		SELECT key, failed_count, lockout_count, locked_until, last_failure_at
+		FROM login_attempts
+		WHERE key = $1
+	`
+
+	attempt := &domain.LoginAttempt{}
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&attempt.Key,
+		&attempt.FailedCount,
+		&attempt.LockoutCount,
+		&attempt.LockedUntil,
+		&attempt.LastFailureAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrLoginAttemptNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// RecordFailure increments the failure counter of key and returns the updated attempt.
+// Failures older than window no longer count, and the lockout escalation
+// is forgotten once no failure happened for lockoutReset.
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration, lockoutReset time.Duration) (*domain.LoginAttempt, error) {
+	now := time.Now()
+	query := `
+		INSERT INTO login_attempts (key, failed_count, lockout_count, last_failure_at)
+		VALUES ($1, 1, 0, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failed_count = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failed_count + 1 END,
+			lockout_count = CASE WHEN login_attempts.last_failure_at < $4 THEN 0 ELSE login_attempts.lockout_count END,
+			last_failure_at = $2
+		RETURNING key, failed_count, lockout_count, locked_until, last_failure_at
+	`
+
+	attempt := &domain.LoginAttempt{}
+	err := r.db.QueryRowContext(ctx, query, key, now, now.Add(-window), now.Add(-lockoutReset)).Scan(
+		&attempt.Key,
+		&attempt.FailedCount,
+		&attempt.LockoutCount,
+		&attempt.LockedUntil,
+		&attempt.LastFailureAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// Lock locks key until the given time and starts a new failure count
+func (r *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `
+		UPDATE login_attempts
+		SET locked_until = $2, lockout_count = lockout_count + 1, failed_count = 0
+		WHERE key = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, key, until)
+	return err
+}
+
+func (r *loginAttemptRepository) Delete(ctx context.Context, key string) error {
+	query := `DELETE FROM login_attempts WHERE key = $1`
+
+	result, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrLoginAttemptNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// MFARepository defines the interface for two-factor authentication data operations
+type MFARepository interface {
+	Get(ctx context.Context, userID string) (*domain.UserMFA, error)
+	SavePending(ctx context.Context, mfa *domain.UserMFA) error
+	Enable(ctx context.Context, userID string, step int64) error
+	UseStep(ctx context.Context, userID string, step int64) error
+	Delete(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string) error
+	CountRecoveryCodes(ctx context.Context, userID string) (int, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// OIDCRepository defines the interface for single sign-on data operations
+type OIDCRepository interface {
+	GetIdentity(ctx context.Context, issuer, subject string) (*domain.ExternalIdentity, error)
+	CreateIdentity(ctx context.Context, identity *domain.ExternalIdentity) error
+	// TouchIdentity records a login and the email the provider currently reports
+	TouchIdentity(ctx context.Context, issuer, subject, email string) error
+	SaveState(ctx context.Context, state *domain.OIDCLoginState) error
+	// ConsumeState deletes an unexpired login state and returns it, so it can only be used once
+	ConsumeState(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error)
+}
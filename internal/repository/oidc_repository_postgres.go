@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type oidcRepository struct {
+	db *sql.DB
+}
+
+// NewOIDCRepository creates a new single sign-on repository
+func NewOIDCRepository(db *sql.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+func (r *oidcRepository) GetIdentity(ctx context.Context, issuer, subject string) (*domain.ExternalIdentity, error) {
+	query := `
+		SELECT issuer, subject, user_id, COALESCE(email, ''), created_at, last_login_at
+		FROM user_identities
+		WHERE issuer = $1 AND subject = $2
+	`
+
+	identity := &domain.ExternalIdentity{}
+	err := r.db.QueryRowContext(ctx, query, issuer, subject).Scan(
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrIdentityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (r *oidcRepository) CreateIdentity(ctx context.Context, identity *domain.ExternalIdentity) error {
+	query := `
+		INSERT INTO user_identities (issuer, subject, user_id, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		identity.Issuer,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+		identity.CreatedAt,
+		identity.LastLoginAt,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *oidcRepository) TouchIdentity(ctx context.Context, issuer, subject, email string) error {
+	query := `
+		UPDATE user_identities
+		SET email = NULLIF($3, ''), last_login_at = $4
+		WHERE issuer = $1 AND subject = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, issuer, subject, email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrIdentityNotFound
+	}
+
+	return nil
+}
+
+func (r *oidcRepository) SaveState(ctx context.Context, state *domain.OIDCLoginState) error {
+	// Abandoned logins are cleaned up whenever a new one starts
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM oidc_login_states WHERE expires_at <= $1`, time.Now()); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		state.StateHash,
+		state.Nonce,
+		state.CodeVerifier,
+		state.ExpiresAt,
+		state.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *oidcRepository) ConsumeState(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1 AND expires_at > $2
+		RETURNING state_hash, nonce, code_verifier, expires_at, created_at
+	`
+
+	state := &domain.OIDCLoginState{}
+	err := r.db.QueryRowContext(ctx, query, stateHash, time.Now()).Scan(
+		&state.StateHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+		&state.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOIDCStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
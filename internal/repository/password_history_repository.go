@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// PasswordHistoryRepository defines the interface for password history data operations
+type PasswordHistoryRepository interface {
+	// Add stores entry and deletes all but the keep newest entries of the user
+	Add(ctx context.Context, entry *domain.PasswordHistoryEntry, keep int) error
+	// ListRecent returns up to limit entries of the user, newest first
+	ListRecent(ctx context.Context, userID string, limit int) ([]*domain.PasswordHistoryEntry, error)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+)
+
+type passwordHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordHistoryRepository creates a new password history repository
+func NewPasswordHistoryRepository(db *sql.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+func (r *passwordHistoryRepository) Add(ctx context.Context, entry *domain.PasswordHistoryEntry, keep int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO password_history (user_id, password_hash, created_at) VALUES ($1, $2, $3)`,
+		entry.UserID, entry.PasswordHash, entry.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		)
+	`
+
+	_, err = tx.ExecContext(ctx, query, entry.UserID, keep)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *passwordHistoryRepository) ListRecent(ctx context.Context, userID string, limit int) ([]*domain.PasswordHistoryEntry, error) {
+	query := `
+		SELECT user_id, password_hash, created_at
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.PasswordHistoryEntry{}
+	for rows.Next() {
+		entry := &domain.PasswordHistoryEntry{}
+		err := rows.Scan(&entry.UserID, &entry.PasswordHash, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
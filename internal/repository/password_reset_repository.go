@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// PasswordResetRepository defines the interface for password reset data operations
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *domain.PasswordReset) error
+	ConsumeToken(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+	// Release makes a consumed token usable again, e.g. when the new password was rejected
+	Release(ctx context.Context, id string) error
+	DeleteByUserID(ctx context.Context, userID string) error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository
+func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, reset *domain.PasswordReset) error {
+	query := `
+		INSERT INTO password_resets (id, user_id, token_hash, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		reset.ID,
+		reset.UserID,
+		reset.TokenHash,
+		reset.CreatedBy,
+		reset.ExpiresAt,
+		time.Now(),
+	)
+	return err
+}
+
+// ConsumeToken marks an unused, unexpired reset token as used and returns it.
+// Marking and checking happen in one statement so a token can only be used once.
+func (r *passwordResetRepository) ConsumeToken(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	query := `
+		UPDATE password_resets
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, token_hash, COALESCE(created_by, ''), expires_at, used_at, created_at
+	`
+
+	reset := &domain.PasswordReset{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(
+		&reset.ID,
+		&reset.UserID,
+		&reset.TokenHash,
+		&reset.CreatedBy,
+		&reset.ExpiresAt,
+		&reset.UsedAt,
+		&reset.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrResetTokenInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reset, nil
+}
+
+func (r *passwordResetRepository) Release(ctx context.Context, id string) error {
+	query := `UPDATE password_resets SET used_at = NULL WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *passwordResetRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	query := `DELETE FROM password_resets WHERE user_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
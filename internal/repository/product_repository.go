@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// ProductFilter narrows and pages the products returned by List
+type ProductFilter struct {
+	// Search matches part of the SKU, name or barcode, case-insensitive
+	Search     string
+	CategoryID string
+	// IncludeSubcategories also matches products of every category below CategoryID
+	IncludeSubcategories bool
+	UnitOfMeasure        string
+	// Active matches active or inactive products; every product when nil
+	Active *bool
+	Limit  int
+	Offset int
+}
+
+// ProductRepository defines the interface for product data operations
+type ProductRepository interface {
+	Create(ctx context.Context, product *domain.Product) error
+	GetByID(ctx context.Context, id string) (*domain.Product, error)
+	GetBySKU(ctx context.Context, sku string) (*domain.Product, error)
+	// List returns one page of products and the total number of matching products
+	List(ctx context.Context, filter ProductFilter) ([]*domain.Product, int, error)
+	Update(ctx context.Context, product *domain.Product) error
+	Delete(ctx context.Context, id string) error
+}
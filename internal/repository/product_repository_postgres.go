@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+)
+
+type productRepository struct {
+	db *sql.DB
+}
+
+// NewProductRepository creates a new product repository
+func NewProductRepository(db *sql.DB) ProductRepository {
+	return &productRepository{db: db}
+}
+
+func (r *productRepository) Create(ctx context.Context, product *domain.Product) error {
+	query := `
+		INSERT INTO products (id, sku, name, description, category_id, unit_of_measure, barcode, cost, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		product.ID,
+		product.SKU,
+		product.Name,
+		product.Description,
+		product.CategoryID,
+		product.UnitOfMeasure,
+		product.Barcode,
+		product.Cost,
+		product.Active,
+		product.CreatedAt,
+		product.UpdatedAt,
+	)
+
+	return productWriteError(err)
+}
+
+// productColumns are the columns read by scanProduct
+const productColumns = `
+	id, sku, name, description, COALESCE(category_id, ''), unit_of_measure, barcode, cost, active, created_at, updated_at
+`
+
+func (r *productRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1`
+
+	return r.get(ctx, query, id)
+}
+
+func (r *productRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE sku = $1`
+
+	return r.get(ctx, query, sku)
+}
+
+func (r *productRepository) get(ctx context.Context, query string, args ...any) (*domain.Product, error) {
+	product, err := scanProduct(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrProductNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) List(ctx context.Context, filter ProductFilter) ([]*domain.Product, int, error) {
+	where := `
+		WHERE ($1 = '' OR sku ILIKE '%' || $1 || '%' OR name ILIKE '%' || $1 || '%' OR barcode ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR category_id = $2 OR ($5 AND category_id IN (
+				WITH RECURSIVE subtree AS (
+					SELECT id FROM categories WHERE parent_id = $2
+					UNION ALL
+					SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+				)
+				SELECT id FROM subtree
+			)))
+			AND ($3 = '' OR unit_of_measure = $3)
+			AND ($4::BOOLEAN IS NULL OR active = $4)
+	`
+	args := []any{filter.Search, filter.CategoryID, filter.UnitOfMeasure, filter.Active, filter.IncludeSubcategories}
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products` + where + `
+		ORDER BY sku
+		LIMIT $6 OFFSET $7
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	products := []*domain.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, nil
+}
+
+func (r *productRepository) Update(ctx context.Context, product *domain.Product) error {
+	query := `
+		UPDATE products
+		SET sku = $2, name = $3, description = $4, category_id = NULLIF($5, ''), unit_of_measure = $6,
+			barcode = $7, cost = $8, active = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		product.ID,
+		product.SKU,
+		product.Name,
+		product.Description,
+		product.CategoryID,
+		product.UnitOfMeasure,
+		product.Barcode,
+		product.Cost,
+		product.Active,
+		product.UpdatedAt,
+	)
+	if err != nil {
+		return productWriteError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrProductNotFound
+	}
+
+	return nil
+}
+
+func (r *productRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM products WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrProductInUse
+		}
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrProductNotFound
+	}
+
+	return nil
+}
+
+// productWriteError maps constraint violations of product writes to domain errors
+func productWriteError(err error) error {
+	if isUniqueViolation(err) {
+		if violatedConstraint(err) == "products_barcode_key" {
+			return domain.ErrBarcodeAlreadyUsed
+		}
+		return domain.ErrSKUAlreadyUsed
+	}
+	// The category or unit was deleted in the meantime
+	if isForeignKeyViolation(err) {
+		if violatedConstraint(err) == "products_unit_of_measure_fkey" {
+			return domain.ErrUoMNotFound
+		}
+		return domain.ErrCategoryNotFound
+	}
+	return err
+}
+
+func scanProduct(row rowScanner) (*domain.Product, error) {
+	product := &domain.Product{}
+	err := row.Scan(
+		&product.ID,
+		&product.SKU,
+		&product.Name,
+		&product.Description,
+		&product.CategoryID,
+		&product.UnitOfMeasure,
+		&product.Barcode,
+		&product.Cost,
+		&product.Active,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
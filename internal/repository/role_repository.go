@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// RoleRepository defines the interface for role and permission data operations
+type RoleRepository interface {
+	List(ctx context.Context) ([]*domain.Role, error)
+	GetByName(ctx context.Context, name domain.UserRole) (*domain.Role, error)
+	Create(ctx context.Context, role *domain.Role) error
+	Update(ctx context.Context, role *domain.Role) error
+	Delete(ctx context.Context, name domain.UserRole) error
+	ListPermissions(ctx context.Context) ([]*domain.PermissionInfo, error)
+	GetUserRoles(ctx context.Context, userID string) ([]domain.UserRole, error)
+	GetUserPermissions(ctx context.Context, userID string) ([]domain.Permission, error)
+	SetUserRoles(ctx context.Context, userID string, roles []domain.UserRole) error
+}
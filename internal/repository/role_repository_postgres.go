@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *sql.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]*domain.Role, error) {
+	query := `
+		SELECT r.name, r.description, r.created_at, r.updated_at,
+			COALESCE(STRING_AGG(rp.permission, ',' ORDER BY rp.permission), '')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name
+		ORDER BY r.name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*domain.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name domain.UserRole) (*domain.Role, error) {
+	query := `
+		SELECT r.name, r.description, r.created_at, r.updated_at,
+			COALESCE(STRING_AGG(rp.permission, ',' ORDER BY rp.permission), '')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		WHERE r.name = $1
+		GROUP BY r.name
+	`
+
+	role, err := scanRole(r.db.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRoleNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *domain.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO roles (name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+	`
+
+	_, err = tx.ExecContext(ctx, query, role.Name, role.Description, time.Now())
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrRoleAlreadyExists
+		}
+		return err
+	}
+
+	if err := insertRolePermissions(ctx, tx, role); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update replaces the description and the permissions of a role
+func (r *roleRepository) Update(ctx context.Context, role *domain.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE roles SET description = $2, updated_at = $3 WHERE name = $1`,
+		role.Name, role.Description, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRoleNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role = $1`, role.Name); err != nil {
+		return err
+	}
+
+	if err := insertRolePermissions(ctx, tx, role); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *roleRepository) Delete(ctx context.Context, name domain.UserRole) error {
+	query := `DELETE FROM roles WHERE name = $1`
+
+	result, err := r.db.ExecContext(ctx, query, name)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrRoleInUse
+		}
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRoleNotFound
+	}
+
+	return nil
+}
+
+func (r *roleRepository) ListPermissions(ctx context.Context) ([]*domain.PermissionInfo, error) {
+	query := `SELECT name, description FROM permissions ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []*domain.PermissionInfo{}
+	for rows.Next() {
+		permission := &domain.PermissionInfo{}
+		if err := rows.Scan(&permission.Name, &permission.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) GetUserRoles(ctx context.Context, userID string) ([]domain.UserRole, error) {
+	query := `SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []domain.UserRole{}
+	for rows.Next() {
+		var role domain.UserRole
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetUserPermissions(ctx context.Context, userID string) ([]domain.Permission, error) {
+	query := `
+		SELECT DISTINCT rp.permission
+		FROM user_roles ur
+		JOIN role_permissions rp ON rp.role = ur.role
+		WHERE ur.user_id = $1
+		ORDER BY rp.permission
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []domain.Permission{}
+	for rows.Next() {
+		var permission domain.Permission
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// SetUserRoles replaces the roles of a user.
+// The first role becomes the primary role stored on the user.
+func (r *roleRepository) SetUserRoles(ctx context.Context, userID string, roles []domain.UserRole) error {
+	if len(roles) == 0 {
+		return domain.ErrUserRolesRequired
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE users SET role = $2, updated_at = $3 WHERE id = $1`,
+		userID, roles[0], time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO user_roles (user_id, role) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			userID, role,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return domain.ErrRoleNotFound
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertRolePermissions(ctx context.Context, tx *sql.Tx, role *domain.Role) error {
+	for _, permission := range role.Permissions {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO role_permissions (role, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			role.Name, permission,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return domain.ErrPermissionNotFound
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRole(row rowScanner) (*domain.Role, error) {
+	role := &domain.Role{}
+	var permissions string
+	err := row.Scan(
+		&role.Name,
+		&role.Description,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+		&permissions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Permissions are aggregated as a comma separated list
+	role.Permissions = []domain.Permission{}
+	if permissions != "" {
+		for _, permission := range strings.Split(permissions, ",") {
+			role.Permissions = append(role.Permissions, domain.Permission(permission))
+		}
+	}
+
+	return role, nil
+}
+
+// isUniqueViolation checks if err is a PostgreSQL unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation checks if err is a PostgreSQL foreign key violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// violatedConstraint returns the name of the constraint err violates, if any
+func violatedConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
+// checkAffected returns notFound when no row was changed
+func checkAffected(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
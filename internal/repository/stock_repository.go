@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"time"
+)
+
+// MovementFilter narrows and pages the movements returned by ListMovements
+type MovementFilter struct {
+	ProductID     string
+	LocationID    string
+	WarehouseID   string
+	PostingID     string
+	Type          domain.MovementType
+	ReferenceType string
+	ReferenceID   string
+	// From and To limit the movements to those created at or after From and before To when set
+	From *time.Time
+	To   *time.Time
+	// UserID limits the movements to the active warehouses assigned to the user when set
+	UserID string
+	Limit  int
+	Offset int
+}
+
+// BalanceFilter narrows and pages the balances returned by ListBalances
+type BalanceFilter struct {
+	ProductID   string
+	LocationID  string
+	WarehouseID string
+	// IncludeZero also returns balances of locations the product left
+	IncludeZero bool
+	// UserID limits the balances to the active warehouses assigned to the user when set
+	UserID string
+	Limit  int
+	Offset int
+}
+
+// StockRepository defines the interface for the stock ledger and its balances.
+// Postings must go through a UnitOfWork so movements and balances change together.
+type StockRepository interface {
+	// ApplyMovement adds the quantity of movement to the balance of its product and
+	// location and appends movement to the ledger, setting Sequence and BalanceAfter.
+	// The balance stays locked until the transaction ends, so concurrent postings
+	// of the same product and location wait for each other. A balance that would
+	// turn negative returns domain.ErrInsufficientStock, a quantity or balance
+	// beyond the range of the columns domain.ErrQuantityOutOfRange.
+	ApplyMovement(ctx context.Context, movement *domain.StockMovement) error
+	GetPosting(ctx context.Context, postingID string) (*domain.StockPosting, error)
+	// ListMovements returns one page of movements, latest first, and the total number of matching movements
+	ListMovements(ctx context.Context, filter MovementFilter) ([]*domain.StockMovement, int, error)
+	// ListBalances returns one page of balances ordered by location and SKU, and the total number of matching balances
+	ListBalances(ctx context.Context, filter BalanceFilter) ([]*domain.StockBalance, int, error)
+	// HasMovements checks if the ledger holds movements of the product
+	HasMovements(ctx context.Context, productID string) (bool, error)
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type stockRepository struct {
+	db DBTX
+}
+
+// NewStockRepository creates a new stock repository on a database or a transaction
+func NewStockRepository(db DBTX) StockRepository {
+	return &stockRepository{db: db}
+}
+
+func (r *stockRepository) ApplyMovement(ctx context.Context, movement *domain.StockMovement) error {
+	// The upsert locks the balance row, later postings of it wait for this transaction
+	balanceQuery := `
+		INSERT INTO stock_balances (product_id, location_id, warehouse_id, quantity, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (product_id, location_id)
+		DO UPDATE SET quantity = stock_balances.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+		RETURNING quantity
+	`
+
+	err := r.db.QueryRowContext(ctx, balanceQuery,
+		movement.ProductID,
+		movement.LocationID,
+		movement.WarehouseID,
+		movement.Quantity,
+		movement.CreatedAt,
+	).Scan(&movement.BalanceAfter)
+	if err != nil {
+		if isCheckViolation(err) {
+			return domain.ErrInsufficientStock
+		}
+		if isNumericOutOfRange(err) {
+			return domain.ErrQuantityOutOfRange
+		}
+		// The product or location was deleted in the meantime
+		if isForeignKeyViolation(err) {
+			if violatedConstraint(err) == "stock_balances_product_id_fkey" {
+				return domain.ErrProductNotFound
+			}
+			return domain.ErrLocationNotFound
+		}
+		return err
+	}
+
+	movementQuery := `
+		INSERT INTO stock_movements (
+			id, posting_id, product_id, location_id, warehouse_id, quantity, uom,
+			entered_quantity, entered_uom, balance_after, movement_type,
+			reference_type, reference_id, note, reverses_id, user_id, api_key_id, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), NULLIF($16, ''), NULLIF($17, ''), $18)
+		RETURNING sequence
+	`
+
+	err = r.db.QueryRowContext(ctx, movementQuery,
+		movement.ID,
+		movement.PostingID,
+		movement.ProductID,
+		movement.LocationID,
+		movement.WarehouseID,
+		movement.Quantity,
+		movement.UoM,
+		movement.EnteredQuantity,
+		movement.EnteredUoM,
+		movement.BalanceAfter,
+		movement.Type,
+		movement.ReferenceType,
+		movement.ReferenceID,
+		movement.Note,
+		movement.ReversesID,
+		movement.UserID,
+		movement.APIKeyID,
+		movement.CreatedAt,
+	).Scan(&movement.Sequence)
+	if err != nil {
+		if isUniqueViolation(err) && violatedConstraint(err) == "stock_movements_reverses_id_key" {
+			return domain.ErrPostingReversed
+		}
+		if isNumericOutOfRange(err) {
+			return domain.ErrQuantityOutOfRange
+		}
+		return err
+	}
+
+	return nil
+}
+
+// movementColumns are the columns read by scanMovement
+const movementColumns = `
+	id, sequence, posting_id, product_id, location_id, warehouse_id, quantity, uom,
+	entered_quantity, entered_uom, balance_after, movement_type,
+	reference_type, reference_id, note, COALESCE(reverses_id, ''),
+	COALESCE(user_id, ''), COALESCE(api_key_id, ''), created_at
+`
+
+func (r *stockRepository) GetPosting(ctx context.Context, postingID string) (*domain.StockPosting, error) {
+	query := `SELECT ` + movementColumns + ` FROM stock_movements WHERE posting_id = $1 ORDER BY sequence`
+
+	rows, err := r.db.QueryContext(ctx, query, postingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posting := &domain.StockPosting{ID: postingID}
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			return nil, err
+		}
+		posting.Movements = append(posting.Movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(posting.Movements) == 0 {
+		return nil, domain.ErrPostingNotFound
+	}
+
+	return posting, nil
+}
+
+func (r *stockRepository) ListMovements(ctx context.Context, filter MovementFilter) ([]*domain.StockMovement, int, error) {
+	where := `
+		WHERE ($1 = '' OR product_id = $1)
+			AND ($2 = '' OR location_id = $2)
+			AND ($3 = '' OR warehouse_id = $3)
+			AND ($4 = '' OR posting_id = $4)
+			AND ($5 = '' OR movement_type = $5)
+			AND ($6 = '' OR reference_type = $6)
+			AND ($7 = '' OR reference_id = $7)
+			AND ($8::TIMESTAMPTZ IS NULL OR created_at >= $8)
+			AND ($9::TIMESTAMPTZ IS NULL OR created_at < $9)
+			AND ($10 = '' OR warehouse_id IN (` + operableWarehouses("$10") + `))
+	`
+	args := []any{
+		filter.ProductID, filter.LocationID, filter.WarehouseID, filter.PostingID, filter.Type,
+		filter.ReferenceType, filter.ReferenceID, filter.From, filter.To, filter.UserID,
+	}
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM stock_movements`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + movementColumns + `
+		FROM stock_movements` + where + `
+		ORDER BY sequence DESC
+		LIMIT $11 OFFSET $12
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := []*domain.StockMovement{}
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+func (r *stockRepository) ListBalances(ctx context.Context, filter BalanceFilter) ([]*domain.StockBalance, int, error) {
+	where := `
+		WHERE ($1 = '' OR b.product_id = $1)
+			AND ($2 = '' OR b.location_id = $2)
+			AND ($3 = '' OR b.warehouse_id = $3)
+			AND ($4 OR b.quantity <> 0)
+			AND ($5 = '' OR b.warehouse_id IN (` + operableWarehouses("$5") + `))
+	`
+	args := []any{filter.ProductID, filter.LocationID, filter.WarehouseID, filter.IncludeZero, filter.UserID}
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM stock_balances b`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT b.product_id, p.sku, b.location_id, l.code, b.warehouse_id, b.quantity, p.unit_of_measure, b.updated_at
+		FROM stock_balances b
+		JOIN products p ON p.id = b.product_id
+		JOIN locations l ON l.id = b.location_id` + where + `
+		ORDER BY b.warehouse_id, l.code, p.sku
+		LIMIT $6 OFFSET $7
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	balances := []*domain.StockBalance{}
+	for rows.Next() {
+		balance := &domain.StockBalance{}
+		err := rows.Scan(
+			&balance.ProductID,
+			&balance.SKU,
+			&balance.LocationID,
+			&balance.LocationCode,
+			&balance.WarehouseID,
+			&balance.Quantity,
+			&balance.UoM,
+			&balance.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		balances = append(balances, balance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return balances, total, nil
+}
+
+func (r *stockRepository) HasMovements(ctx context.Context, productID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM stock_movements WHERE product_id = $1)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, productID).Scan(&exists)
+	return exists, err
+}
+
+func scanMovement(row rowScanner) (*domain.StockMovement, error) {
+	movement := &domain.StockMovement{}
+	err := row.Scan(
+		&movement.ID,
+		&movement.Sequence,
+		&movement.PostingID,
+		&movement.ProductID,
+		&movement.LocationID,
+		&movement.WarehouseID,
+		&movement.Quantity,
+		&movement.UoM,
+		&movement.EnteredQuantity,
+		&movement.EnteredUoM,
+		&movement.BalanceAfter,
+		&movement.Type,
+		&movement.ReferenceType,
+		&movement.ReferenceID,
+		&movement.Note,
+		&movement.ReversesID,
+		&movement.UserID,
+		&movement.APIKeyID,
+		&movement.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// isCheckViolation checks if err is a PostgreSQL check constraint violation
+func isCheckViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23514"
+}
+
+// isNumericOutOfRange checks if err is a PostgreSQL numeric value out of range error,
+// raised when a value does not fit a NUMERIC column
+func isNumericOutOfRange(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "22003"
+}
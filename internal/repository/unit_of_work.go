@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is what repositories need from *sql.DB and *sql.Tx, so they can run inside a transaction
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// TxRepositories are repositories bound to the transaction of a unit of work
+type TxRepositories struct {
+	Stock StockRepository
+}
+
+// UnitOfWork runs repository operations in one database transaction
+type UnitOfWork interface {
+	// Do runs fn in a transaction that is committed when fn returns nil and
+	// rolled back otherwise. When the database aborts the transaction because
+	// of a deadlock or a serialization failure, fn runs again in a new
+	// transaction, so fn must not keep state between runs.
+	Do(ctx context.Context, fn func(repos *TxRepositories) error) error
+}
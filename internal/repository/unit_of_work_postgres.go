@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxTxAttempts bounds the runs of a unit of work the database keeps aborting
+const maxTxAttempts = 3
+
+type unitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a new unit of work
+func NewUnitOfWork(db *sql.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos *TxRepositories) error) error {
+	for attempt := 1; ; attempt++ {
+		err := u.run(ctx, fn)
+		if attempt < maxTxAttempts && isRetryableTxError(err) {
+			continue
+		}
+		return err
+	}
+}
+
+// run runs fn once in a new transaction
+func (u *unitOfWork) run(ctx context.Context, fn func(repos *TxRepositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(&TxRepositories{
+		Stock: NewStockRepository(tx),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// isRetryableTxError checks if err is a PostgreSQL serialization failure or deadlock
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "40001" || pgErr.Code == "40P01")
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// UoMRepository defines the interface for units of measure and the units of products
+type UoMRepository interface {
+	Create(ctx context.Context, unit *domain.UnitOfMeasure) error
+	GetByCode(ctx context.Context, code string) (*domain.UnitOfMeasure, error)
+	List(ctx context.Context) ([]*domain.UnitOfMeasure, error)
+	Update(ctx context.Context, unit *domain.UnitOfMeasure) error
+	Delete(ctx context.Context, code string) error
+
+	// ListProductUnits returns the units of the product except its base unit
+	ListProductUnits(ctx context.Context, productID string) ([]*domain.ProductUnit, error)
+	GetProductUnit(ctx context.Context, productID, uom string) (*domain.ProductUnit, error)
+	// SetProductUnit adds the unit to the product or replaces its factor
+	SetProductUnit(ctx context.Context, unit *domain.ProductUnit) error
+	DeleteProductUnit(ctx context.Context, productID, uom string) error
+}
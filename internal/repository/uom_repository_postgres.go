@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+)
+
+type uomRepository struct {
+	db *sql.DB
+}
+
+// NewUoMRepository creates a new unit of measure repository
+func NewUoMRepository(db *sql.DB) UoMRepository {
+	return &uomRepository{db: db}
+}
+
+func (r *uomRepository) Create(ctx context.Context, unit *domain.UnitOfMeasure) error {
+	query := `
+		INSERT INTO units_of_measure (code, name, decimals, rounding, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		unit.Code,
+		unit.Name,
+		unit.Decimals,
+		unit.Rounding,
+		unit.CreatedAt,
+		unit.UpdatedAt,
+	)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrUoMAlreadyExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *uomRepository) GetByCode(ctx context.Context, code string) (*domain.UnitOfMeasure, error) {
+	query := `SELECT code, name, decimals, rounding, created_at, updated_at FROM units_of_measure WHERE code = $1`
+
+	unit := &domain.UnitOfMeasure{}
+	err := r.db.QueryRowContext(ctx, query, code).Scan(
+		&unit.Code,
+		&unit.Name,
+		&unit.Decimals,
+		&unit.Rounding,
+		&unit.CreatedAt,
+		&unit.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUoMNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unit, nil
+}
+
+func (r *uomRepository) List(ctx context.Context) ([]*domain.UnitOfMeasure, error) {
+	query := `SELECT code, name, decimals, rounding, created_at, updated_at FROM units_of_measure ORDER BY code`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := []*domain.UnitOfMeasure{}
+	for rows.Next() {
+		unit := &domain.UnitOfMeasure{}
+		err := rows.Scan(
+			&unit.Code,
+			&unit.Name,
+			&unit.Decimals,
+			&unit.Rounding,
+			&unit.CreatedAt,
+			&unit.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, unit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return units, nil
+}
+
+func (r *uomRepository) Update(ctx context.Context, unit *domain.UnitOfMeasure) error {
+	query := `
+		UPDATE units_of_measure
+		SET name = $2, decimals = $3, rounding = $4, updated_at = $5
+		WHERE code = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, unit.Code, unit.Name, unit.Decimals, unit.Rounding, unit.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result, domain.ErrUoMNotFound)
+}
+
+func (r *uomRepository) Delete(ctx context.Context, code string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM units_of_measure WHERE code = $1`, code)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrUoMInUse
+		}
+		return err
+	}
+
+	return checkAffected(result, domain.ErrUoMNotFound)
+}
+
+func (r *uomRepository) ListProductUnits(ctx context.Context, productID string) ([]*domain.ProductUnit, error) {
+	query := `
+		SELECT product_id, uom, factor, created_at, updated_at
+		FROM product_units
+		WHERE product_id = $1
+		ORDER BY factor, uom
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := []*domain.ProductUnit{}
+	for rows.Next() {
+		unit, err := scanProductUnit(rows)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, unit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return units, nil
+}
+
+func (r *uomRepository) GetProductUnit(ctx context.Context, productID, uom string) (*domain.ProductUnit, error) {
+	query := `
+		SELECT product_id, uom, factor, created_at, updated_at
+		FROM product_units
+		WHERE product_id = $1 AND uom = $2
+	`
+
+	unit, err := scanProductUnit(r.db.QueryRowContext(ctx, query, productID, uom))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrProductUnitNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unit, nil
+}
+
+func (r *uomRepository) SetProductUnit(ctx context.Context, unit *domain.ProductUnit) error {
+	query := `
+		INSERT INTO product_units (product_id, uom, factor, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (product_id, uom) DO UPDATE SET factor = EXCLUDED.factor, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, unit.ProductID, unit.UoM, unit.Factor, unit.UpdatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			if violatedConstraint(err) == "product_units_uom_fkey" {
+				return domain.ErrUoMNotFound
+			}
+			return domain.ErrProductNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *uomRepository) DeleteProductUnit(ctx context.Context, productID, uom string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM product_units WHERE product_id = $1 AND uom = $2`, productID, uom)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result, domain.ErrProductUnitNotFound)
+}
+
+func scanProductUnit(row rowScanner) (*domain.ProductUnit, error) {
+	unit := &domain.ProductUnit{}
+	err := row.Scan(
+		&unit.ProductID,
+		&unit.UoM,
+		&unit.Factor,
+		&unit.CreatedAt,
+		&unit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
@@ -5,11 +5,29 @@ import (
 	"context"
 )
 
-// UserRepository defines the interface for user data operations
+// UserFilter narrows and pages the users returned by List
+type UserFilter struct {
+	// Search matches part of the username or fullname, case-insensitive
+	Search string
+	// Role matches users holding the role, primary or not
+	Role domain.UserRole
+	// Status matches one status; deactivated users are excluded when empty
+	Status domain.UserStatus
+	Limit  int
+	Offset int
+}
+
+// UserRepository defines the interface for user data operations.
+// Lookups skip deactivated users unless their name says otherwise.
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByID(ctx context.Context, id string) (*domain.User, error)
+	GetByIDIncludingDeactivated(ctx context.Context, id string) (*domain.User, error)
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	GetByUsernameIncludingDeactivated(ctx context.Context, username string) (*domain.User, error)
+	// List returns one page of users and the total number of matching users
+	List(ctx context.Context, filter UserFilter) ([]*domain.User, int, error)
 	Update(ctx context.Context, user *domain.User) error
+	SetStatus(ctx context.Context, id string, status domain.UserStatus, reason string) error
 	Delete(ctx context.Context, id string) error
 }
@@ -17,12 +17,18 @@ func NewUserRepository(db *sql.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO users (id, username, password, fullname, role, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		user.ID,
 		user.Username,
 		user.Password,
@@ -34,33 +40,60 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 
 	if err != nil {
 		// Check for unique constraint violation
-		if err.Error() == "pq: duplicate key value violates unique constraint \"users_username_key\"" {
+		if isUniqueViolation(err) {
 			return domain.ErrUsernameAlreadyUsed
 		}
 		return err
 	}
 
-	return nil
+	// The primary role is also the first entry of the user roles
+	_, err = tx.ExecContext(ctx, `INSERT INTO user_roles (user_id, role) VALUES ($1, $2)`, user.ID, user.Role)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrRoleNotFound
+		}
+		return err
+	}
+
+	return tx.Commit()
 }
 
+// userColumns are the columns read by scanUser
+const userColumns = `
+	id, username, password, fullname, role, created_at, updated_at,
+	status, status_reason, status_changed_at
+`
+
+// GetByID returns an active or suspended user
 func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	query := `
-		SELECT id, username, password, fullname, role, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND status <> 'deactivated'`
 
-	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Password,
-		&user.Fullname,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	return r.get(ctx, query, id)
+}
+
+// GetByIDIncludingDeactivated returns a user in any status, e.g. to display who created a record
+func (r *userRepository) GetByIDIncludingDeactivated(ctx context.Context, id string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
+	return r.get(ctx, query, id)
+}
+
+// GetByUsername returns an active or suspended user
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1 AND status <> 'deactivated'`
+
+	return r.get(ctx, query, username)
+}
+
+// GetByUsernameIncludingDeactivated returns a user in any status
+func (r *userRepository) GetByUsernameIncludingDeactivated(ctx context.Context, username string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1`
+
+	return r.get(ctx, query, username)
+}
+
+func (r *userRepository) get(ctx context.Context, query string, args ...any) (*domain.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, args...))
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrUserNotFound
 	}
@@ -72,43 +105,74 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User,
 	return user, nil
 }
 
-func (r *userRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+func (r *userRepository) List(ctx context.Context, filter UserFilter) ([]*domain.User, int, error) {
+	// Deactivated users are only listed when asked for explicitly
+	where := `
+		WHERE ($1 = '' OR username ILIKE '%' || $1 || '%' OR fullname ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = users.id AND ur.role = $2))
+			AND (status = $3 OR ($3 = '' AND status <> 'deactivated'))
+	`
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+where, filter.Search, filter.Role, filter.Status).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	query := `
-		SELECT id, username, password, fullname, role, created_at, updated_at
-		FROM users
-		WHERE username = $1
+		SELECT ` + userColumns + `
+		FROM users` + where + `
+		ORDER BY username
+		LIMIT $4 OFFSET $5
 	`
 
-	user := &domain.User{}
-	err := r.db.QueryRowContext(ctx, query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Password,
-		&user.Fullname,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	rows, err := r.db.QueryContext(ctx, query, filter.Search, filter.Role, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
 
-	if err == sql.ErrNoRows {
-		return nil, domain.ErrUserNotFound
+	users := []*domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
 	}
 
-	if err != nil {
-		return nil, err
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	return user, nil
+	return users, total, nil
 }
 
+// Update stores the user. When the primary role changes, the previous
+// primary role is replaced by the new one in the user roles.
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previousRole domain.UserRole
+	err = tx.QueryRowContext(ctx, `SELECT role FROM users WHERE id = $1 FOR UPDATE`, user.ID).Scan(&previousRole)
+	if err == sql.ErrNoRows {
+		return domain.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE users
 		SET username = $2, password = $3, fullname = $4, role = $5, updated_at = $6
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		user.ID,
 		user.Username,
 		user.Password,
@@ -117,6 +181,41 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 		time.Now(),
 	)
 
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameAlreadyUsed
+		}
+		return err
+	}
+
+	if previousRole != user.Role {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`, user.ID, previousRole); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO user_roles (user_id, role) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			user.ID, user.Role,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return domain.ErrRoleNotFound
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *userRepository) SetStatus(ctx context.Context, id string, status domain.UserStatus, reason string) error {
+	query := `
+		UPDATE users
+		SET status = $2, status_reason = $3, status_changed_at = $4, updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, status, reason, time.Now())
 	if err != nil {
 		return err
 	}
@@ -138,6 +237,9 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrUserInUse
+		}
 		return err
 	}
 
@@ -152,3 +254,23 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	user := &domain.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Password,
+		&user.Fullname,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Status,
+		&user.StatusReason,
+		&user.StatusChangedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+)
+
+// WarehouseFilter narrows the warehouses returned by List
+type WarehouseFilter struct {
+	// Search matches part of the code or name, case-insensitive
+	Search string
+	// Active matches active or inactive warehouses; every warehouse when nil
+	Active *bool
+	// UserID limits the warehouses to those assigned to the user, or the API key acting as one, when set
+	UserID string
+}
+
+// WarehouseRepository defines the interface for warehouse data operations and user assignments
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *domain.Warehouse) error
+	GetByID(ctx context.Context, id string) (*domain.Warehouse, error)
+	List(ctx context.Context, filter WarehouseFilter) ([]*domain.Warehouse, error)
+	Update(ctx context.Context, warehouse *domain.Warehouse) error
+	Delete(ctx context.Context, id string) error
+
+	// IsAssigned checks if the user or API key is assigned to the warehouse and the warehouse is active
+	IsAssigned(ctx context.Context, userID, warehouseID string) (bool, error)
+	// SetUserWarehouses replaces the warehouses assigned to the user
+	SetUserWarehouses(ctx context.Context, userID string, warehouseIDs []string) error
+	// SetAPIKeyWarehouses replaces the warehouses assigned to the API key
+	SetAPIKeyWarehouses(ctx context.Context, apiKeyID string, warehouseIDs []string) error
+	// ListUsers returns the users assigned to the warehouse
+	ListUsers(ctx context.Context, warehouseID string) ([]*domain.User, error)
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"api-stockflow/internal/domain"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type warehouseRepository struct {
+	db *sql.DB
+}
+
+// NewWarehouseRepository creates a new warehouse repository
+func NewWarehouseRepository(db *sql.DB) WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	query := `
+		INSERT INTO warehouses (id, code, name, address, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		warehouse.ID,
+		warehouse.Code,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.Active,
+		warehouse.CreatedAt,
+		warehouse.UpdatedAt,
+	)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrWarehouseCodeUsed
+		}
+		return err
+	}
+
+	return nil
+}
+
+// warehouseColumns are the columns read by scanWarehouse
+const warehouseColumns = `id, code, name, address, active, created_at, updated_at`
+
+func (r *warehouseRepository) GetByID(ctx context.Context, id string) (*domain.Warehouse, error) {
+	query := `SELECT ` + warehouseColumns + ` FROM warehouses WHERE id = $1`
+
+	warehouse, err := scanWarehouse(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrWarehouseNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+func (r *warehouseRepository) List(ctx context.Context, filter WarehouseFilter) ([]*domain.Warehouse, error) {
+	query := `
+		SELECT ` + warehouseColumns + `
+		FROM warehouses
+		WHERE ($1 = '' OR code ILIKE '%' || $1 || '%' OR name ILIKE '%' || $1 || '%')
+			AND ($2::BOOLEAN IS NULL OR active = $2)
+			AND ($3 = '' OR id IN (` + assignedWarehouses("$3") + `))
+		ORDER BY code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.Search, filter.Active, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	warehouses := []*domain.Warehouse{}
+	for rows.Next() {
+		warehouse, err := scanWarehouse(rows)
+		if err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, warehouse)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return warehouses, nil
+}
+
+func (r *warehouseRepository) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	query := `
+		UPDATE warehouses
+		SET code = $2, name = $3, address = $4, active = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		warehouse.ID,
+		warehouse.Code,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.Active,
+		warehouse.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrWarehouseCodeUsed
+		}
+		return err
+	}
+
+	return checkAffected(result, domain.ErrWarehouseNotFound)
+}
+
+func (r *warehouseRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM warehouses WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return domain.ErrWarehouseInUse
+		}
+		return err
+	}
+
+	return checkAffected(result, domain.ErrWarehouseNotFound)
+}
+
+func (r *warehouseRepository) IsAssigned(ctx context.Context, userID, warehouseID string) (bool, error) {
+	query := `SELECT $2 IN (` + operableWarehouses("$1") + `)`
+
+	var assigned bool
+	err := r.db.QueryRowContext(ctx, query, userID, warehouseID).Scan(&assigned)
+	return assigned, err
+}
+
+func (r *warehouseRepository) SetUserWarehouses(ctx context.Context, userID string, warehouseIDs []string) error {
+	return r.setAssignments(ctx, "user_warehouses", "user_id", userID, warehouseIDs, domain.ErrUserNotFound)
+}
+
+func (r *warehouseRepository) SetAPIKeyWarehouses(ctx context.Context, apiKeyID string, warehouseIDs []string) error {
+	return r.setAssignments(ctx, "api_key_warehouses", "api_key_id", apiKeyID, warehouseIDs, domain.ErrAPIKeyNotFound)
+}
+
+// setAssignments replaces the warehouses assigned to ownerID in table, whose owner column is
+// ownerColumn. A missing owner returns ownerNotFound.
+func (r *warehouseRepository) setAssignments(
+	ctx context.Context,
+	table, ownerColumn, ownerID string,
+	warehouseIDs []string,
+	ownerNotFound error,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE `+ownerColumn+` = $1`, ownerID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, warehouseID := range warehouseIDs {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO `+table+` (`+ownerColumn+`, warehouse_id, created_at) VALUES ($1, $2, $3)`,
+			ownerID, warehouseID, now,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				if violatedConstraint(err) == table+"_"+ownerColumn+"_fkey" {
+					return ownerNotFound
+				}
+				return domain.ErrWarehouseNotFound
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *warehouseRepository) ListUsers(ctx context.Context, warehouseID string) ([]*domain.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id IN (SELECT user_id FROM user_warehouses WHERE warehouse_id = $1)
+		ORDER BY username
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// assignedWarehouses selects the IDs of the warehouses assigned to the user or
+// API key whose ID is the query parameter param. Both kinds of IDs share one
+// namespace since API key principals act as users.
+func assignedWarehouses(param string) string {
+	return `
+		SELECT warehouse_id FROM user_warehouses WHERE user_id = ` + param + `
+		UNION ALL
+		SELECT warehouse_id FROM api_key_warehouses WHERE api_key_id = ` + param + `
+	`
+}
+
+// operableWarehouses selects the IDs of the active warehouses assigned to the
+// user or API key whose ID is the query parameter param
+func operableWarehouses(param string) string {
+	return `SELECT id FROM warehouses WHERE active AND id IN (` + assignedWarehouses(param) + `)`
+}
+
+func scanWarehouse(row rowScanner) (*domain.Warehouse, error) {
+	warehouse := &domain.Warehouse{}
+	err := row.Scan(
+		&warehouse.ID,
+		&warehouse.Code,
+		&warehouse.Name,
+		&warehouse.Address,
+		&warehouse.Active,
+		&warehouse.CreatedAt,
+		&warehouse.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
@@ -0,0 +1,73 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// breachPrefixLength is the number of SHA-1 hex characters naming a range file
+const breachPrefixLength = 5
+
+// BreachedPasswords checks passwords against a list of passwords known from data breaches
+type BreachedPasswords interface {
+	Contains(password string) (bool, error)
+}
+
+type prefixFileBreachedPasswords struct {
+	dir string
+}
+
+// NewBreachedPasswords creates a breached password list read from dir.
+//
+// The list uses the k-anonymity range format of Have I Been Pwned: dir holds
+// one file per SHA-1 prefix of five hex characters, named ABCDE or ABCDE.txt,
+// listing the remaining 35 characters of each hash as SUFFIX or SUFFIX:COUNT
+// lines. Only the file of the password's prefix is read. No password is
+// considered breached when dir is empty.
+func NewBreachedPasswords(dir string) BreachedPasswords {
+	return &prefixFileBreachedPasswords{dir: dir}
+}
+
+func (b *prefixFileBreachedPasswords) Contains(password string) (bool, error) {
+	if b.dir == "" {
+		return false, nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breachPrefixLength], hash[breachPrefixLength:]
+
+	file, err := b.openRange(prefix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(line), suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// openRange opens the range file of prefix, with or without .txt extension
+func (b *prefixFileBreachedPasswords) openRange(prefix string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(b.dir, prefix))
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.Open(filepath.Join(b.dir, prefix+".txt"))
+	}
+	return file, err
+}
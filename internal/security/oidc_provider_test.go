@@ -0,0 +1,301 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "stockflow"
+	testNonce    = "nonce-1"
+)
+
+// fakeProvider is a local OpenID Connect provider. The token endpoint returns
+// the ID token set with issue, signed by the key with the kid of the token header.
+type fakeProvider struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	idToken string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+
+	p := &fakeProvider{keys: map[string]*rsa.PrivateKey{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, oidcDiscovery{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		set := JSONWebKeySet{Keys: []JSONWebKey{}}
+		for kid, key := range p.keys {
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kty: "RSA",
+				Use: "sig",
+				Alg: "RS256",
+				Kid: kid,
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		writeJSON(w, set)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "authorization_code" || r.FormValue("code_verifier") == "" {
+			http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+			return
+		}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		writeJSON(w, map[string]string{"id_token": p.idToken})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// rotate replaces the published keys with a new key of the given kid
+func (p *fakeProvider) rotate(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = map[string]*rsa.PrivateKey{kid: key}
+	return key
+}
+
+// claims returns valid ID token claims for the test client
+func (p *fakeProvider) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":                p.server.URL,
+		"sub":                "subject-1",
+		"aud":                testClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"nonce":              testNonce,
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"groups":             []string{"stockflow-admins"},
+		"amr":                []string{"pwd", "mfa"},
+	}
+}
+
+// issue makes the token endpoint return claims signed by key under kid
+func (p *fakeProvider) issue(t *testing.T, claims jwt.MapClaims, kid string, key *rsa.PrivateKey) {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idToken = signed
+}
+
+func (p *fakeProvider) client() *oidcProvider {
+	return NewOIDCProvider(OIDCConfig{
+		IssuerURL:   p.server.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost:3000/sso/callback",
+		HTTPClient:  p.server.Client(),
+	}).(*oidcProvider)
+}
+
+func TestOIDCProviderExchange(t *testing.T) {
+	provider := newFakeProvider(t)
+	key := provider.rotate(t, "key-1")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		claims  func(claims jwt.MapClaims)
+		kid     string
+		key     *rsa.PrivateKey
+		nonce   string
+		wantErr string
+	}{
+		{name: "valid token", kid: "key-1", key: key, nonce: testNonce},
+		{name: "bad signature", kid: "key-1", key: otherKey, nonce: testNonce, wantErr: "signature is invalid"},
+		{
+			name:    "wrong audience",
+			claims:  func(claims jwt.MapClaims) { claims["aud"] = "other-client" },
+			kid:     "key-1",
+			key:     key,
+			nonce:   testNonce,
+			wantErr: "audience",
+		},
+		{name: "wrong nonce", kid: "key-1", key: key, nonce: "nonce-2", wantErr: "nonce mismatch"},
+		{
+			name:    "missing nonce",
+			claims:  func(claims jwt.MapClaims) { delete(claims, "nonce") },
+			kid:     "key-1",
+			key:     key,
+			nonce:   testNonce,
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "expired token",
+			claims: func(claims jwt.MapClaims) {
+				claims["iat"] = time.Now().Add(-time.Hour).Unix()
+				claims["exp"] = time.Now().Add(-10 * time.Minute).Unix()
+			},
+			kid:     "key-1",
+			key:     key,
+			nonce:   testNonce,
+			wantErr: "expired",
+		},
+		{
+			name:    "wrong issuer",
+			claims:  func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+			kid:     "key-1",
+			key:     key,
+			nonce:   testNonce,
+			wantErr: "issuer",
+		},
+		{
+			name: "foreign authorized party",
+			claims: func(claims jwt.MapClaims) {
+				claims["aud"] = []string{testClientID, "other-client"}
+				claims["azp"] = "other-client"
+			},
+			kid:     "key-1",
+			key:     key,
+			nonce:   testNonce,
+			wantErr: "authorized party mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := provider.claims()
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			provider.issue(t, claims, tt.kid, tt.key)
+
+			result, err := provider.client().Exchange(context.Background(), "code-1", "verifier-1", tt.nonce)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Exchange() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+
+			if result.Issuer != provider.server.URL || result.Subject != "subject-1" || result.PreferredUsername != "alice" {
+				t.Errorf("Exchange() = %+v, want the claims of the token", result)
+			}
+			if len(result.Groups) != 1 || result.Groups[0] != "stockflow-admins" {
+				t.Errorf("Exchange() groups = %v, want [stockflow-admins]", result.Groups)
+			}
+			if len(result.AMR) != 2 || result.AMR[1] != "mfa" {
+				t.Errorf("Exchange() amr = %v, want [pwd mfa]", result.AMR)
+			}
+		})
+	}
+}
+
+func TestOIDCProviderKeyRotation(t *testing.T) {
+	provider := newFakeProvider(t)
+	client := provider.client()
+
+	oldKey := provider.rotate(t, "key-1")
+	provider.issue(t, provider.claims(), "key-1", oldKey)
+	if _, err := client.Exchange(context.Background(), "code-1", "verifier-1", testNonce); err != nil {
+		t.Fatalf("Exchange() with the first key error = %v", err)
+	}
+
+	// The provider publishes a new key and signs with it from now on
+	newKey := provider.rotate(t, "key-2")
+	provider.issue(t, provider.claims(), "key-2", newKey)
+
+	// Unknown kids refresh the key set at most once per jwksRefreshInterval
+	_, err := client.Exchange(context.Background(), "code-2", "verifier-2", testNonce)
+	if err == nil || !strings.Contains(err.Error(), `unknown signing key "key-2"`) {
+		t.Fatalf("Exchange() right after the last download error = %v, want unknown signing key", err)
+	}
+
+	client.mu.Lock()
+	client.keysFetchedAt = time.Now().Add(-jwksRefreshInterval)
+	client.mu.Unlock()
+
+	if _, err := client.Exchange(context.Background(), "code-3", "verifier-3", testNonce); err != nil {
+		t.Fatalf("Exchange() with the rotated key error = %v", err)
+	}
+
+	// Tokens of the retired key are refused once the new set is loaded
+	provider.issue(t, provider.claims(), "key-1", oldKey)
+	_, err = client.Exchange(context.Background(), "code-4", "verifier-4", testNonce)
+	if err == nil || !strings.Contains(err.Error(), `unknown signing key "key-1"`) {
+		t.Fatalf("Exchange() with the retired key error = %v, want unknown signing key", err)
+	}
+}
+
+func TestOIDCProviderAuthCodeURL(t *testing.T) {
+	provider := newFakeProvider(t)
+
+	authorizationURL, err := provider.client().AuthCodeURL(context.Background(), "state-1", testNonce, "challenge-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+
+	for _, want := range []string{
+		provider.server.URL + "/authorize?",
+		"state=state-1",
+		"nonce=" + testNonce,
+		"code_challenge=challenge-1",
+		"code_challenge_method=S256",
+		"client_id=" + testClientID,
+	} {
+		if !strings.Contains(authorizationURL, want) {
+			t.Errorf("AuthCodeURL() = %s, missing %s", authorizationURL, want)
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package security
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 // PasswordHash handles password hashing operations
 type PasswordHash interface {
 	Hash(password string) (string, error)
 	Compare(hashedPassword, password string) error
+	// NeedsRehash reports whether hashedPassword uses another algorithm or
+	// weaker parameters than the ones new hashes are created with
+	NeedsRehash(hashedPassword string) bool
 }
 
 type bcryptPasswordHash struct {
@@ -14,8 +21,13 @@ type bcryptPasswordHash struct {
 
 // NewBcryptPasswordHash creates a new bcrypt password hash handler
 func NewBcryptPasswordHash() PasswordHash {
+	return NewBcryptPasswordHashWithCost(bcrypt.DefaultCost)
+}
+
+// NewBcryptPasswordHashWithCost creates a new bcrypt password hash handler with the given cost
+func NewBcryptPasswordHashWithCost(cost int) PasswordHash {
 	return &bcryptPasswordHash{
-		cost: bcrypt.DefaultCost,
+		cost: cost,
 	}
 }
 
@@ -30,3 +42,61 @@ func (b *bcryptPasswordHash) Hash(password string) (string, error) {
 func (b *bcryptPasswordHash) Compare(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+func (b *bcryptPasswordHash) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return true
+	}
+	return cost < b.cost
+}
+
+// PasswordHashConfig holds the password hash settings
+type PasswordHashConfig struct {
+	// Algorithm of new hashes, "argon2id" or "bcrypt"
+	Algorithm  string
+	BcryptCost int
+	Argon2id   Argon2idParams
+}
+
+// NewPasswordHash creates the password hash handler of config.
+// New hashes use the configured algorithm, while hashes of either
+// algorithm can still be compared.
+func NewPasswordHash(config PasswordHashConfig) PasswordHash {
+	bcryptHash := NewBcryptPasswordHashWithCost(config.BcryptCost)
+	argon2Hash := NewArgon2idPasswordHash(config.Argon2id)
+
+	preferred := argon2Hash
+	if config.Algorithm == "bcrypt" {
+		preferred = bcryptHash
+	}
+
+	return &multiPasswordHash{
+		preferred: preferred,
+		bcrypt:    bcryptHash,
+		argon2id:  argon2Hash,
+	}
+}
+
+// multiPasswordHash creates hashes with the preferred algorithm and
+// compares existing hashes with the algorithm recorded in them
+type multiPasswordHash struct {
+	preferred PasswordHash
+	bcrypt    PasswordHash
+	argon2id  PasswordHash
+}
+
+func (m *multiPasswordHash) Hash(password string) (string, error) {
+	return m.preferred.Hash(password)
+}
+
+func (m *multiPasswordHash) Compare(hashedPassword, password string) error {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return m.argon2id.Compare(hashedPassword, password)
+	}
+	return m.bcrypt.Compare(hashedPassword, password)
+}
+
+func (m *multiPasswordHash) NeedsRehash(hashedPassword string) bool {
+	return m.preferred.NeedsRehash(hashedPassword)
+}
@@ -0,0 +1,139 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// ErrArgon2idMismatch is returned when a password does not match an argon2id hash
+var ErrArgon2idMismatch = errors.New("argon2id: hashed password does not match")
+
+// ErrArgon2idInvalidHash is returned when a hash is not in the encoded argon2id format
+var ErrArgon2idInvalidHash = errors.New("argon2id: invalid encoded hash")
+
+// Argon2idParams holds the argon2id cost parameters
+type Argon2idParams struct {
+	Memory      uint32 // Memory in KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams follows the OWASP recommendation for argon2id
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+type argon2idPasswordHash struct {
+	params Argon2idParams
+}
+
+// NewArgon2idPasswordHash creates a new argon2id password hash handler.
+// Hashes are encoded as $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+// so the parameters used for each hash are known when comparing.
+func NewArgon2idPasswordHash(params Argon2idParams) PasswordHash {
+	return &argon2idPasswordHash{
+		params: params,
+	}
+}
+
+func (a *argon2idPasswordHash) Hash(password string) (string, error) {
+	salt := make([]byte, a.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.params.Iterations, a.params.Memory, a.params.Parallelism, a.params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		a.params.Memory,
+		a.params.Iterations,
+		a.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (a *argon2idPasswordHash) Compare(hashedPassword, password string) error {
+	params, salt, key, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return ErrArgon2idMismatch
+	}
+
+	return nil
+}
+
+func (a *argon2idPasswordHash) NeedsRehash(hashedPassword string) bool {
+	params, _, _, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory < a.params.Memory ||
+		params.Iterations < a.params.Iterations ||
+		params.Parallelism < a.params.Parallelism ||
+		params.KeyLength < a.params.KeyLength
+}
+
+func decodeArgon2idHash(encoded string) (*Argon2idParams, []byte, []byte, error) {
+	if !strings.HasPrefix(encoded, argon2idPrefix) {
+		return nil, nil, nil, ErrArgon2idInvalidHash
+	}
+
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, ErrArgon2idInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrArgon2idInvalidHash
+	}
+
+	params := &Argon2idParams{}
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return nil, nil, nil, ErrArgon2idInvalidHash
+	}
+	// argon2.IDKey panics on zero iterations or parallelism
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return nil, nil, nil, ErrArgon2idInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrArgon2idInvalidHash
+	}
+	params.SaltLength = uint32(len(salt))
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	// An empty key would match every password
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrArgon2idInvalidHash
+	}
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
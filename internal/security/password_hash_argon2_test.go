@@ -0,0 +1,140 @@
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2idParams keeps the tests fast, the format is the same as with the defaults
+var testArgon2idParams = Argon2idParams{
+	Memory:      64,
+	Iterations:  2,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func mustHash(t *testing.T, hash PasswordHash, password string) string {
+	t.Helper()
+	hashed, err := hash.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	return hashed
+}
+
+func TestArgon2idPasswordHashCompare(t *testing.T) {
+	hash := NewArgon2idPasswordHash(testArgon2idParams)
+	hashed := mustHash(t, hash, "correct horse")
+
+	if !strings.HasPrefix(hashed, "$argon2id$v=19$m=64,t=2,p=2$") {
+		t.Fatalf("Hash() = %s, want the encoded parameters", hashed)
+	}
+
+	parts := strings.Split(hashed, "$")
+
+	tests := []struct {
+		name     string
+		hashed   string
+		password string
+		err      error
+	}{
+		{name: "round trip", hashed: hashed, password: "correct horse"},
+		{name: "wrong password", hashed: hashed, password: "correct horsE", err: ErrArgon2idMismatch},
+		{name: "empty password", hashed: hashed, password: "", err: ErrArgon2idMismatch},
+		{name: "not a hash", hashed: "correct horse", password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "argon2i hash", hashed: strings.Replace(hashed, "$argon2id$", "$argon2i$", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "missing key", hashed: strings.Join(parts[:5], "$"), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "other version", hashed: strings.Replace(hashed, "v=19", "v=16", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "missing parameter", hashed: strings.Replace(hashed, ",p=2", "", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "zero parallelism", hashed: strings.Replace(hashed, "p=2", "p=0", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "parallelism overflow", hashed: strings.Replace(hashed, "p=2", "p=256", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "zero iterations", hashed: strings.Replace(hashed, "t=2", "t=0", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "zero memory", hashed: strings.Replace(hashed, "m=64", "m=0", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "negative memory", hashed: strings.Replace(hashed, "m=64", "m=-64", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "invalid salt", hashed: strings.Replace(hashed, parts[4], "!!", 1), password: "correct horse", err: ErrArgon2idInvalidHash},
+		{name: "empty key", hashed: strings.TrimSuffix(hashed, parts[5]), password: "correct horse", err: ErrArgon2idInvalidHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := hash.Compare(tt.hashed, tt.password); err != tt.err {
+				t.Errorf("Compare(%q) error = %v, want %v", tt.hashed, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestArgon2idPasswordHashNeedsRehash(t *testing.T) {
+	hash := NewArgon2idPasswordHash(testArgon2idParams)
+
+	bcryptHashed, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	withParams := func(change func(params *Argon2idParams)) string {
+		params := testArgon2idParams
+		change(&params)
+		return mustHash(t, NewArgon2idPasswordHash(params), "correct horse")
+	}
+
+	tests := []struct {
+		name   string
+		hashed string
+		want   bool
+	}{
+		{name: "current parameters", hashed: mustHash(t, hash, "correct horse"), want: false},
+		{name: "bcrypt hash", hashed: string(bcryptHashed), want: true},
+		{name: "less memory", hashed: withParams(func(params *Argon2idParams) { params.Memory = 32 }), want: true},
+		{name: "fewer iterations", hashed: withParams(func(params *Argon2idParams) { params.Iterations = 1 }), want: true},
+		{name: "lower parallelism", hashed: withParams(func(params *Argon2idParams) { params.Parallelism = 1 }), want: true},
+		{name: "shorter key", hashed: withParams(func(params *Argon2idParams) { params.KeyLength = 16 }), want: true},
+		{name: "stronger parameters", hashed: withParams(func(params *Argon2idParams) { params.Memory = 128; params.Iterations = 3 }), want: false},
+		{name: "malformed hash", hashed: "$argon2id$v=19$m=64,t=2,p=0$c2FsdA$a2V5", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hash.NeedsRehash(tt.hashed); got != tt.want {
+				t.Errorf("NeedsRehash(%q) = %v, want %v", tt.hashed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordHashNeedsRehash(t *testing.T) {
+	argon2Hashed := mustHash(t, NewArgon2idPasswordHash(testArgon2idParams), "correct horse")
+	bcryptHashed := mustHash(t, NewBcryptPasswordHashWithCost(bcrypt.MinCost), "correct horse")
+
+	tests := []struct {
+		name      string
+		algorithm string
+		hashed    string
+		want      bool
+	}{
+		{name: "argon2id preferred, argon2id hash", algorithm: "argon2id", hashed: argon2Hashed, want: false},
+		{name: "argon2id preferred, bcrypt hash", algorithm: "argon2id", hashed: bcryptHashed, want: true},
+		{name: "bcrypt preferred, bcrypt hash", algorithm: "bcrypt", hashed: bcryptHashed, want: false},
+		{name: "bcrypt preferred, argon2id hash", algorithm: "bcrypt", hashed: argon2Hashed, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := NewPasswordHash(PasswordHashConfig{
+				Algorithm:  tt.algorithm,
+				BcryptCost: bcrypt.MinCost,
+				Argon2id:   testArgon2idParams,
+			})
+
+			if err := hash.Compare(tt.hashed, "correct horse"); err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if got := hash.NeedsRehash(tt.hashed); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package security
+
+import (
+	"api-stockflow/internal/domain"
+	"sync"
+	"time"
+)
+
+// PrincipalCache keeps recently authenticated users with their roles and
+// permissions so the auth middleware does not query them on every request.
+// Entries must be invalidated whenever a user, its roles or a role changes.
+type PrincipalCache interface {
+	Get(userID string) (*domain.User, bool)
+	Set(user *domain.User)
+	Invalidate(userID string)
+	// Clear drops every entry, e.g. after the permissions of a role changed
+	Clear()
+}
+
+type memoryPrincipalCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]principalEntry
+}
+
+type principalEntry struct {
+	user      domain.User
+	expiresAt time.Time
+}
+
+// NewMemoryPrincipalCache creates an in-process principal cache
+func NewMemoryPrincipalCache(ttl time.Duration) PrincipalCache {
+	return &memoryPrincipalCache{
+		ttl:     ttl,
+		entries: make(map[string]principalEntry),
+	}
+}
+
+// Get returns a copy of the cached user so callers cannot change the entry
+func (c *memoryPrincipalCache) Get(userID string) (*domain.User, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[userID]
+	c.mu.RUnlock()
+
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return nil, false
+	}
+
+	user := entry.user
+	return &user, true
+}
+
+func (c *memoryPrincipalCache) Set(user *domain.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop expired entries while holding the lock anyway
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+
+	c.entries[user.ID] = principalEntry{user: *user, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *memoryPrincipalCache) Invalidate(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, userID)
+}
+
+func (c *memoryPrincipalCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]principalEntry)
+}
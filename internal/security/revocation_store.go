@@ -0,0 +1,147 @@
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+// revocationSweepInterval is how often expired revocations are dropped
+const revocationSweepInterval = time.Minute
+
+// RevocationStore is a denylist of access tokens that must stop working
+// before they expire. Entries only need to live as long as an access token.
+type RevocationStore interface {
+	// RevokeToken revokes one access token by its jti until it expires
+	RevokeToken(tokenID string, expiresAt time.Time)
+	// RevokeSession revokes every access token issued for a session
+	RevokeSession(sessionID string)
+	// RevokeUser revokes every access token issued to a user so far
+	RevokeUser(userID string)
+	IsRevoked(payload *TokenPayload) bool
+}
+
+type memoryRevocationStore struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	tokens    map[string]time.Time // jti -> expiry
+	sessions  map[string]time.Time // session ID -> expiry
+	users     map[string]userRevocation
+	lastSweep time.Time
+}
+
+type userRevocation struct {
+	before    time.Time
+	expiresAt time.Time
+}
+
+// NewMemoryRevocationStore creates an in-process revocation store.
+// ttl must be at least the access token lifetime.
+func NewMemoryRevocationStore(ttl time.Duration) RevocationStore {
+	return &memoryRevocationStore{
+		ttl:       ttl,
+		tokens:    make(map[string]time.Time),
+		sessions:  make(map[string]time.Time),
+		users:     make(map[string]userRevocation),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryRevocationStore) RevokeToken(tokenID string, expiresAt time.Time) {
+	if tokenID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[tokenID] = expiresAt
+	s.sweep()
+}
+
+func (s *memoryRevocationStore) RevokeSession(sessionID string) {
+	if sessionID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[sessionID] = time.Now().Add(s.ttl)
+	s.sweep()
+}
+
+func (s *memoryRevocationStore) RevokeUser(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.users[userID] = userRevocation{before: now, expiresAt: now.Add(s.ttl)}
+	s.sweep()
+}
+
+func (s *memoryRevocationStore) IsRevoked(payload *TokenPayload) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+
+	if expiresAt, ok := s.tokens[payload.ID]; ok && now.Before(expiresAt) {
+		return true
+	}
+
+	if expiresAt, ok := s.sessions[payload.SessionID]; ok && now.Before(expiresAt) {
+		return true
+	}
+
+	if s.isUserRevoked(payload.UserID, payload, now) {
+		return true
+	}
+
+	// Revoking the admin also ends the impersonations they started
+	return payload.IsImpersonation() && s.isUserRevoked(payload.Actor.UserID, payload, now)
+}
+
+// isUserRevoked checks if the tokens of userID issued so far were revoked.
+// Callers hold the read lock.
+func (s *memoryRevocationStore) isUserRevoked(userID string, payload *TokenPayload, now time.Time) bool {
+	revocation, ok := s.users[userID]
+	if !ok || !now.Before(revocation.expiresAt) {
+		return false
+	}
+
+	switch {
+	case payload.IssuedAtMillis > 0:
+		return payload.IssuedAtMillis <= revocation.before.UnixMilli()
+	case payload.IssuedAt != nil:
+		// Tokens without iat_ms only carry seconds, so those issued within the
+		// same second as the revocation are treated as revoked as well
+		return payload.IssuedAt.Unix() <= revocation.before.Unix()
+	}
+
+	return true
+}
+
+// sweep drops expired entries, at most once per interval. Callers hold the write lock.
+func (s *memoryRevocationStore) sweep() {
+	now := time.Now()
+	if now.Sub(s.lastSweep) < revocationSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for id, expiresAt := range s.tokens {
+		if !now.Before(expiresAt) {
+			delete(s.tokens, id)
+		}
+	}
+	for id, expiresAt := range s.sessions {
+		if !now.Before(expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	for id, revocation := range s.users {
+		if !now.Before(revocation.expiresAt) {
+			delete(s.users, id)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRevokeUser(t *testing.T) {
+	store := NewMemoryRevocationStore(time.Hour)
+
+	issuedBefore := time.Now()
+	store.RevokeUser("user-1")
+	issuedAfter := time.Now().Add(time.Millisecond)
+
+	tests := []struct {
+		name    string
+		payload *TokenPayload
+		want    bool
+	}{
+		{
+			name:    "issued before the revocation",
+			payload: &TokenPayload{UserID: "user-1", IssuedAtMillis: issuedBefore.UnixMilli()},
+			want:    true,
+		},
+		{
+			name:    "issued after the revocation within the same second",
+			payload: &TokenPayload{UserID: "user-1", IssuedAtMillis: issuedAfter.UnixMilli()},
+			want:    false,
+		},
+		{
+			name: "without iat_ms issued within the same second",
+			payload: &TokenPayload{
+				UserID:           "user-1",
+				RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedBefore)},
+			},
+			want: true,
+		},
+		{
+			name: "without iat_ms issued seconds later",
+			payload: &TokenPayload{
+				UserID:           "user-1",
+				RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedBefore.Add(2 * time.Second))},
+			},
+			want: false,
+		},
+		{
+			name:    "without any issue time",
+			payload: &TokenPayload{UserID: "user-1"},
+			want:    true,
+		},
+		{
+			name:    "other user",
+			payload: &TokenPayload{UserID: "user-2", IssuedAtMillis: issuedBefore.UnixMilli()},
+			want:    false,
+		},
+		{
+			name: "impersonation by the revoked admin",
+			payload: &TokenPayload{
+				UserID:         "user-2",
+				Actor:          &ActorClaim{UserID: "user-1"},
+				IssuedAtMillis: issuedBefore.UnixMilli(),
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := store.IsRevoked(tt.payload); got != tt.want {
+				t.Errorf("IsRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
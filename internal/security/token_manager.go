@@ -2,56 +2,176 @@ package security
 
 import (
 	"api-stockflow/internal/domain"
+	"log"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Token types carried in the typ claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	TokenTypeMFA     = "mfa"
+)
+
+// mfaTokenAge is how long a user has to complete the second login step
+const mfaTokenAge = 5 * time.Minute
+
+// refreshTokenAge is the lifetime of refresh tokens
+const refreshTokenAge = 30 * 24 * time.Hour
+
 // TokenManager handles JWT token operations
 type TokenManager interface {
-	GenerateAccessToken(userID string, role domain.UserRole) (string, error)
+	GenerateAccessToken(userID string, role domain.UserRole, sessionID string) (string, error)
+	// GenerateImpersonationToken creates an access token of userID carrying actorID in the act claim.
+	// No refresh token exists for it, it ends after age.
+	GenerateImpersonationToken(userID string, role domain.UserRole, actorID, sessionID string, age time.Duration) (string, error)
 	GenerateRefreshToken(userID string) (string, error)
+	GenerateMFAToken(userID string) (string, error)
 	VerifyAccessToken(tokenString string) (*TokenPayload, error)
 	VerifyRefreshToken(tokenString string) (*TokenPayload, error)
+	VerifyMFAToken(tokenString string) (*TokenPayload, error)
+	JWKS() *JSONWebKeySet
+	// AccessTokenAge is the lifetime of access tokens, revocations must outlive it
+	AccessTokenAge() time.Duration
+	// RefreshTokenAge is the lifetime of refresh tokens, stored sessions expire with it
+	RefreshTokenAge() time.Duration
 }
 
 // TokenPayload represents the JWT token payload
 type TokenPayload struct {
-	UserID string           `json:"user_id"`
-	Role   domain.UserRole  `json:"role"`
+	UserID    string          `json:"user_id"`
+	Role      domain.UserRole `json:"role"`
+	SessionID string          `json:"sid,omitempty"`
+	TokenType string          `json:"typ,omitempty"`
+	Actor     *ActorClaim     `json:"act,omitempty"`
+	// IssuedAtMillis is iat in milliseconds. Revoking a user compares it, so a token
+	// issued right after the revocation, in the same second, keeps working.
+	IssuedAtMillis int64 `json:"iat_ms,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ActorClaim identifies the user acting on behalf of the token subject (RFC 8693)
+type ActorClaim struct {
+	UserID string `json:"sub"`
+}
+
+// IsImpersonation checks if the token was issued to an admin acting as another user
+func (p *TokenPayload) IsImpersonation() bool {
+	return p.Actor != nil
+}
+
 type jwtTokenManager struct {
 	accessTokenKey  string
 	refreshTokenKey string
 	accessTokenAge  int
+
+	// Access tokens are signed with signingKey when it is configured,
+	// otherwise with accessTokenKey using HS256
+	signingKey       *asymmetricKey
+	verificationKeys map[string]*asymmetricKey
 }
 
-// NewJWTTokenManager creates a new JWT token manager
+// NewJWTTokenManager creates a new JWT token manager.
+//
+// Access tokens are signed with HS256 and ACCESS_TOKEN_KEY unless
+// JWT_SIGNING_KEY_FILE points to an RSA or Ed25519 private key, in which case
+// they are signed with RS256 or EdDSA and carry a kid header. Keys listed in
+// JWT_VERIFICATION_KEY_FILES as kid=path pairs are still accepted so keys
+// can be rotated without invalidating tokens already issued.
 func NewJWTTokenManager() TokenManager {
 	accessTokenAge, _ := strconv.Atoi(os.Getenv("ACCESS_TOKEN_AGE"))
 	if accessTokenAge == 0 {
 		accessTokenAge = 3600 // default 1 hour
 	}
 
-	return &jwtTokenManager{
-		accessTokenKey:  os.Getenv("ACCESS_TOKEN_KEY"),
-		refreshTokenKey: os.Getenv("REFRESH_TOKEN_KEY"),
-		accessTokenAge:  accessTokenAge,
+	manager := &jwtTokenManager{
+		accessTokenKey:   os.Getenv("ACCESS_TOKEN_KEY"),
+		refreshTokenKey:  os.Getenv("REFRESH_TOKEN_KEY"),
+		accessTokenAge:   accessTokenAge,
+		verificationKeys: make(map[string]*asymmetricKey),
 	}
+
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+		key, err := loadSigningKey(path, os.Getenv("JWT_SIGNING_KEY_ID"))
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing key: %v", err)
+		}
+		manager.signingKey = key
+		manager.verificationKeys[key.id] = key
+	}
+
+	for _, entry := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, path, found := strings.Cut(entry, "=")
+		if !found {
+			id, path = "", entry
+		}
+
+		key, err := loadVerificationKey(strings.TrimSpace(path), strings.TrimSpace(id))
+		if err != nil {
+			log.Fatalf("Failed to load JWT verification key: %v", err)
+		}
+
+		// The active signing key already holds its private half
+		if _, exists := manager.verificationKeys[key.id]; !exists {
+			manager.verificationKeys[key.id] = key
+		}
+	}
+
+	return manager
 }
 
-func (t *jwtTokenManager) GenerateAccessToken(userID string, role domain.UserRole) (string, error) {
-	claims := TokenPayload{
-		UserID: userID,
-		Role:   role,
+func (t *jwtTokenManager) GenerateAccessToken(userID string, role domain.UserRole, sessionID string) (string, error) {
+	now := time.Now()
+	return t.signAccessToken(TokenPayload{
+		UserID:         userID,
+		Role:           role,
+		SessionID:      sessionID,
+		TokenType:      TokenTypeAccess,
+		IssuedAtMillis: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(t.accessTokenAge) * time.Second)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			// jti lets a single access token be revoked
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(t.accessTokenAge) * time.Second)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+}
+
+func (t *jwtTokenManager) GenerateImpersonationToken(userID string, role domain.UserRole, actorID, sessionID string, age time.Duration) (string, error) {
+	now := time.Now()
+	return t.signAccessToken(TokenPayload{
+		UserID:         userID,
+		Role:           role,
+		SessionID:      sessionID,
+		TokenType:      TokenTypeAccess,
+		Actor:          &ActorClaim{UserID: actorID},
+		IssuedAtMillis: now.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(age)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
+	})
+}
+
+// signAccessToken signs claims with the active signing key
+func (t *jwtTokenManager) signAccessToken(claims TokenPayload) (string, error) {
+	if t.signingKey != nil {
+		token := jwt.NewWithClaims(t.signingKey.method, claims)
+		token.Header["kid"] = t.signingKey.id
+		return token.SignedString(t.signingKey.private)
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -60,9 +180,12 @@ func (t *jwtTokenManager) GenerateAccessToken(userID string, role domain.UserRol
 
 func (t *jwtTokenManager) GenerateRefreshToken(userID string) (string, error) {
 	claims := TokenPayload{
-		UserID: userID,
+		UserID:    userID,
+		TokenType: TokenTypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour * 30)), // 30 days
+			// Unique ID keeps tokens issued within the same second distinct
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTokenAge)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -71,34 +194,114 @@ func (t *jwtTokenManager) GenerateRefreshToken(userID string) (string, error) {
 	return token.SignedString([]byte(t.refreshTokenKey))
 }
 
+// GenerateMFAToken creates the short-lived challenge token issued after a
+// correct password when the second factor is still missing.
+// It is signed with the refresh key because only this service verifies it.
+func (t *jwtTokenManager) GenerateMFAToken(userID string) (string, error) {
+	claims := TokenPayload{
+		UserID:    userID,
+		TokenType: TokenTypeMFA,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(mfaTokenAge)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(t.refreshTokenKey))
+}
+
+// VerifyAccessToken selects the verification key by the kid header.
+// Tokens without kid are HS256 tokens signed with ACCESS_TOKEN_KEY.
 func (t *jwtTokenManager) VerifyAccessToken(tokenString string) (*TokenPayload, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenPayload{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(t.accessTokenKey), nil
+	return t.verify(tokenString, TokenTypeAccess, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if t.accessTokenKey == "" {
+				return nil, domain.ErrInvalidToken
+			}
+			return t.hmacKey(token, t.accessTokenKey)
+		}
+
+		key, ok := t.verificationKeys[kid]
+		if !ok || token.Method.Alg() != key.method.Alg() {
+			return nil, domain.ErrInvalidToken
+		}
+
+		return key.public, nil
+	})
+}
+
+func (t *jwtTokenManager) VerifyRefreshToken(tokenString string) (*TokenPayload, error) {
+	return t.verify(tokenString, TokenTypeRefresh, func(token *jwt.Token) (interface{}, error) {
+		return t.hmacKey(token, t.refreshTokenKey)
 	})
+}
 
+func (t *jwtTokenManager) VerifyMFAToken(tokenString string) (*TokenPayload, error) {
+	claims, err := t.verify(tokenString, TokenTypeMFA, func(token *jwt.Token) (interface{}, error) {
+		return t.hmacKey(token, t.refreshTokenKey)
+	})
 	if err != nil {
-		return nil, domain.ErrInvalidToken
+		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*TokenPayload); ok && token.Valid {
-		return claims, nil
+	// Unlike older access and refresh tokens, MFA tokens always carry their type
+	if claims.TokenType != TokenTypeMFA {
+		return nil, domain.ErrInvalidToken
 	}
 
-	return nil, domain.ErrInvalidToken
+	return claims, nil
 }
 
-func (t *jwtTokenManager) VerifyRefreshToken(tokenString string) (*TokenPayload, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenPayload{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(t.refreshTokenKey), nil
+func (t *jwtTokenManager) AccessTokenAge() time.Duration {
+	return time.Duration(t.accessTokenAge) * time.Second
+}
+
+func (t *jwtTokenManager) RefreshTokenAge() time.Duration {
+	return refreshTokenAge
+}
+
+// JWKS returns the public keys access tokens can be verified with
+func (t *jwtTokenManager) JWKS() *JSONWebKeySet {
+	set := &JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range t.verificationKeys {
+		set.Keys = append(set.Keys, key.jwk())
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
 	})
 
+	return set
+}
+
+// hmacKey returns key for HMAC signed tokens and refuses any other algorithm
+func (t *jwtTokenManager) hmacKey(token *jwt.Token, key string) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	return []byte(key), nil
+}
+
+// verify parses tokenString with the key returned by keyFunc and checks its type.
+// Tokens issued before the typ claim existed carry no type and are accepted.
+func (t *jwtTokenManager) verify(tokenString, tokenType string, keyFunc jwt.Keyfunc) (*TokenPayload, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &TokenPayload{}, keyFunc)
+
 	if err != nil {
 		return nil, domain.ErrInvalidToken
 	}
 
-	if claims, ok := token.Claims.(*TokenPayload); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*TokenPayload)
+	if !ok || !token.Valid {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if claims.TokenType != "" && claims.TokenType != tokenType {
+		return nil, domain.ErrInvalidToken
 	}
 
-	return nil, domain.ErrInvalidToken
+	return claims, nil
 }
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/handler"
+	"api-stockflow/internal/security"
+	"api-stockflow/internal/usecase"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// envString returns the value of key or def when it is not set
+func envString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// envInt returns the integer value of key or def when it is not set or invalid
+func envInt(key string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envIntRange returns the integer value of key or def when it is not set.
+// It exits when the value is invalid or outside min..max.
+func envIntRange(key string, def, min, max int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		log.Fatalf("%s must be an integer between %d and %d, got %q", key, min, max, raw)
+	}
+	return value
+}
+
+// envSeconds returns the duration of key expressed in seconds or def when it is not set or invalid
+func envSeconds(key string, def time.Duration) time.Duration {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return def
+	}
+	return time.Duration(value) * time.Second
+}
+
+// envBool returns the boolean value of key or def when it is not set or invalid
+func envBool(key string, def bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envList returns the comma separated values of key, ignoring empty items
+func envList(key string) []string {
+	var values []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// refreshCookieConfig returns the refresh token cookie settings
+func refreshCookieConfig() handler.RefreshCookieConfig {
+	return handler.RefreshCookieConfig{
+		Enabled:  envBool("REFRESH_TOKEN_COOKIE", false),
+		Name:     envString("REFRESH_TOKEN_COOKIE_NAME", "refresh_token"),
+		CSRFName: envString("CSRF_COOKIE_NAME", "csrf_token"),
+		Path:     "/api/auth",
+		Domain:   envString("REFRESH_TOKEN_COOKIE_DOMAIN", ""),
+		Secure:   envBool("REFRESH_TOKEN_COOKIE_SECURE", true),
+		SameSite: envString("REFRESH_TOKEN_COOKIE_SAMESITE", "Strict"),
+		// Same lifetime as the refresh token itself
+		MaxAge: 30 * 24 * time.Hour,
+	}
+}
+
+// passwordHashConfig returns the password hash settings
+func passwordHashConfig() security.PasswordHashConfig {
+	algorithm := envString("PASSWORD_HASH_ALGORITHM", "argon2id")
+	if algorithm != "argon2id" && algorithm != "bcrypt" {
+		log.Fatalf("PASSWORD_HASH_ALGORITHM must be argon2id or bcrypt, got %q", algorithm)
+	}
+
+	defaults := security.DefaultArgon2idParams
+	return security.PasswordHashConfig{
+		Algorithm:  algorithm,
+		BcryptCost: envIntRange("BCRYPT_COST", bcrypt.DefaultCost, bcrypt.MinCost, bcrypt.MaxCost),
+		Argon2id: security.Argon2idParams{
+			Memory:      uint32(envIntRange("ARGON2_MEMORY", int(defaults.Memory), 1, math.MaxUint32)),
+			Iterations:  uint32(envIntRange("ARGON2_ITERATIONS", int(defaults.Iterations), 1, math.MaxUint32)),
+			Parallelism: uint8(envIntRange("ARGON2_PARALLELISM", int(defaults.Parallelism), 1, math.MaxUint8)),
+			SaltLength:  defaults.SaltLength,
+			KeyLength:   defaults.KeyLength,
+		},
+	}
+}
+
+// mfaConfig returns the two-factor authentication settings
+func mfaConfig() usecase.MFAConfig {
+	var requiredRoles []domain.UserRole
+	for _, role := range envList("MFA_REQUIRED_ROLES") {
+		requiredRoles = append(requiredRoles, domain.UserRole(role))
+	}
+
+	return usecase.MFAConfig{
+		Issuer:        envString("MFA_ISSUER", "StockFlow"),
+		RequiredRoles: requiredRoles,
+	}
+}
+
+// oidcStateCookieConfig returns the single sign-on state cookie settings.
+// The cookie lives as long as the login state.
+func oidcStateCookieConfig(stateAge time.Duration) handler.OIDCStateCookieConfig {
+	return handler.OIDCStateCookieConfig{
+		Name:   envString("OIDC_STATE_COOKIE_NAME", "oidc_state"),
+		Path:   "/api/auth/oidc",
+		Domain: envString("OIDC_STATE_COOKIE_DOMAIN", ""),
+		Secure: envBool("OIDC_STATE_COOKIE_SECURE", true),
+		// Lax is enough: the callback is posted by the SPA, not by the provider
+		SameSite: envString("OIDC_STATE_COOKIE_SAMESITE", "Lax"),
+		MaxAge:   stateAge,
+	}
+}
+
+// oidcGroupRoles parses OIDC_GROUP_ROLES, a comma separated list of group=role pairs
+func oidcGroupRoles() []usecase.OIDCGroupRole {
+	var mappings []usecase.OIDCGroupRole
+	for _, item := range envList("OIDC_GROUP_ROLES") {
+		i := strings.LastIndex(item, "=")
+		if i <= 0 || i == len(item)-1 {
+			continue
+		}
+		mappings = append(mappings, usecase.OIDCGroupRole{
+			Group: strings.TrimSpace(item[:i]),
+			Role:  domain.UserRole(strings.TrimSpace(item[i+1:])),
+		})
+	}
+	return mappings
+}
@@ -1,26 +1,78 @@
 package server
 
 import (
+	"log"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+
+	"api-stockflow/internal/middleware"
 )
 
 func (s *FiberServer) RegisterFiberRoutes() {
+	// Credentials, and therefore the refresh token cookie, require explicit origins
+	allowOrigins := strings.Join(envList("CORS_ALLOW_ORIGINS"), ",")
+	if allowOrigins == "" {
+		allowOrigins = "*"
+	}
+	allowCredentials := allowOrigins != "*"
+
+	if refreshCookieConfig().Enabled && !allowCredentials {
+		log.Fatal("REFRESH_TOKEN_COOKIE requires CORS_ALLOW_ORIGINS to list the allowed origins")
+	}
+
 	// Apply CORS middleware
 	s.App.Use(cors.New(cors.Config{
-		AllowOrigins:     "*",
+		AllowOrigins:     allowOrigins,
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,PATCH",
-		AllowHeaders:     "Accept,Authorization,Content-Type",
-		AllowCredentials: false, // credentials require explicit origins
+		AllowHeaders:     "Accept,Authorization,Content-Type,X-API-Key,X-CSRF-Token",
+		AllowCredentials: allowCredentials,
 		MaxAge:           300,
 	}))
 
+	// Record refused requests of authenticated users in the audit log
+	s.App.Use(middleware.AuditMiddleware(s.audit))
+
 	s.App.Get("/", s.HelloWorldHandler)
 
 	s.App.Get("/health", s.healthHandler)
 
 	// Setup authentication routes
 	s.SetupAuthRoutes()
+
+	// Setup single sign-on routes
+	s.SetupOIDCRoutes()
+
+	// Setup role and permission routes
+	s.SetupRoleRoutes()
+
+	// Setup user management routes
+	s.SetupUserRoutes()
+
+	// Setup impersonation routes
+	s.SetupImpersonationRoutes()
+
+	// Setup approval limit routes
+	s.SetupApprovalRoutes()
+
+	// Setup API key routes
+	s.SetupAPIKeyRoutes()
+
+	// Setup audit log routes
+	s.SetupAuditRoutes()
+
+	// Setup background job routes
+	s.SetupMaintenanceRoutes()
+
+	// Setup product catalog, category and unit of measure routes
+	s.SetupProductRoutes()
+
+	// Setup warehouse and location routes
+	s.SetupWarehouseRoutes()
+
+	// Setup stock ledger routes
+	s.SetupStockRoutes()
 }
 
 func (s *FiberServer) HelloWorldHandler(c *fiber.Ctx) error {
@@ -1,11 +1,16 @@
 package server
 
 import (
+	"context"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 
 	"api-stockflow/internal/database"
+	"api-stockflow/internal/domain"
 	"api-stockflow/internal/handler"
 	"api-stockflow/internal/middleware"
+	"api-stockflow/internal/notification"
 	"api-stockflow/internal/repository"
 	"api-stockflow/internal/security"
 	"api-stockflow/internal/usecase"
@@ -15,6 +20,16 @@ type FiberServer struct {
 	*fiber.App
 
 	db database.Service
+
+	// Shared by every route group so revocations and invalidations apply everywhere
+	revocations security.RevocationStore
+	principals  security.PrincipalCache
+
+	// Security audit log written by the auth use cases and the audit middleware
+	audit usecase.AuditUseCase
+
+	// Deletes expired and revoked refresh tokens in the background
+	tokenJanitor usecase.TokenJanitor
 }
 
 func New() *FiberServer {
@@ -25,31 +40,467 @@ func New() *FiberServer {
 		}),
 
 		db: database.New(),
+
+		// Revocations must outlive the access tokens they revoke
+		revocations: security.NewMemoryRevocationStore(envSeconds("ACCESS_TOKEN_AGE", time.Hour)),
+		principals:  security.NewMemoryPrincipalCache(envSeconds("PRINCIPAL_CACHE_TTL", 30*time.Second)),
 	}
 
+	server.audit = usecase.NewAuditUseCase(repository.NewAuditRepository(server.db.GetDB()))
+
+	// Disabled when another instance or a scheduled job does the cleanup
+	tokenCleanupInterval := envSeconds("TOKEN_CLEANUP_INTERVAL", time.Hour)
+	if !envBool("TOKEN_CLEANUP_ENABLED", true) {
+		tokenCleanupInterval = 0
+	}
+	server.tokenJanitor = usecase.NewTokenJanitor(repository.NewAuthenticationRepository(server.db.GetDB()), usecase.TokenJanitorConfig{
+		Interval:  tokenCleanupInterval,
+		BatchSize: envInt("TOKEN_CLEANUP_BATCH_SIZE", 1000),
+	})
+
 	return server
 }
 
+// StartBackgroundJobs runs the background jobs until ctx is cancelled
+func (s *FiberServer) StartBackgroundJobs(ctx context.Context) {
+	go s.tokenJanitor.Run(ctx)
+}
+
+// authMiddleware creates the authentication middleware used by every route group
+func (s *FiberServer) authMiddleware(tokenManager security.TokenManager) fiber.Handler {
+	userRepo := repository.NewUserRepository(s.db.GetDB())
+	roleRepo := repository.NewRoleRepository(s.db.GetDB())
+	apiKeyRepo := repository.NewAPIKeyRepository(s.db.GetDB())
+
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, roleRepo)
+
+	return middleware.AuthMiddleware(tokenManager, userRepo, roleRepo, s.revocations, s.principals, apiKeyUseCase)
+}
+
+// passwordPolicy creates the policy every new password must meet
+func (s *FiberServer) passwordPolicy(passwordHash security.PasswordHash) usecase.PasswordPolicy {
+	historyRepo := repository.NewPasswordHistoryRepository(s.db.GetDB())
+	breached := security.NewBreachedPasswords(envString("BREACHED_PASSWORDS_DIR", ""))
+
+	return usecase.NewPasswordPolicy(historyRepo, passwordHash, breached, usecase.PasswordPolicyConfig{
+		MinLength:      envInt("PASSWORD_MIN_LENGTH", 10),
+		MinCharClasses: envInt("PASSWORD_MIN_CHAR_CLASSES", 3),
+		HistorySize:    envInt("PASSWORD_HISTORY_SIZE", 5),
+	})
+}
+
 // SetupAuthRoutes sets up authentication routes
 func (s *FiberServer) SetupAuthRoutes() {
 	// Initialize dependencies
 	tokenManager := security.NewJWTTokenManager()
-	passwordHash := security.NewBcryptPasswordHash()
-	
+	passwordHash := security.NewPasswordHash(passwordHashConfig())
+
 	userRepo := repository.NewUserRepository(s.db.GetDB())
 	authRepo := repository.NewAuthenticationRepository(s.db.GetDB())
-	
-	authUseCase := usecase.NewAuthUseCase(userRepo, authRepo, tokenManager, passwordHash)
-	authHandler := handler.NewAuthHandler(authUseCase)
+	resetRepo := repository.NewPasswordResetRepository(s.db.GetDB())
+	attemptRepo := repository.NewLoginAttemptRepository(s.db.GetDB())
+	mfaRepo := repository.NewMFARepository(s.db.GetDB())
+	invitationRepo := repository.NewInvitationRepository(s.db.GetDB())
+
+	loginLimiter := usecase.NewLoginLimiter(attemptRepo, usecase.LoginLimiterConfig{
+		MaxUserAttempts: envInt("LOGIN_MAX_ATTEMPTS", 5),
+		MaxIPAttempts:   envInt("LOGIN_MAX_IP_ATTEMPTS", 20),
+		FailureWindow:   envSeconds("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		BaseLockout:     envSeconds("LOGIN_LOCKOUT_BASE", time.Minute),
+		MaxLockout:      envSeconds("LOGIN_LOCKOUT_MAX", time.Hour),
+		LockoutReset:    envSeconds("LOGIN_LOCKOUT_RESET", 24*time.Hour),
+	})
+
+	mfaUseCase := usecase.NewMFAUseCase(userRepo, mfaRepo, mfaConfig())
+	mfaHandler := handler.NewMFAHandler(mfaUseCase)
+
+	passwordPolicy := s.passwordPolicy(passwordHash)
+
+	authUseCase := usecase.NewAuthUseCase(userRepo, authRepo, tokenManager, passwordHash, passwordPolicy, loginLimiter, mfaUseCase, s.revocations, s.audit, usecase.AuthConfig{
+		AllowSelfRegistration: envBool("ALLOW_SELF_REGISTRATION", false),
+	})
+	authHandler := handler.NewAuthHandler(authUseCase, refreshCookieConfig())
+
+	passwordUseCase := usecase.NewPasswordUseCase(userRepo, authRepo, resetRepo, passwordHash, passwordPolicy, notification.NewNotifier(), s.revocations, usecase.PasswordResetConfig{
+		URL:      envString("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		TokenAge: envSeconds("PASSWORD_RESET_TOKEN_AGE", time.Hour),
+	})
+	passwordHandler := handler.NewPasswordHandler(passwordUseCase)
+
+	invitationUseCase := usecase.NewInvitationUseCase(invitationRepo, userRepo, passwordHash, passwordPolicy, usecase.InvitationConfig{
+		URL:         envString("INVITATION_URL", "http://localhost:3000/accept-invitation"),
+		TokenAge:    envSeconds("INVITATION_TOKEN_AGE", 7*24*time.Hour),
+		MaxTokenAge: envSeconds("INVITATION_MAX_TOKEN_AGE", 30*24*time.Hour),
+	})
+	invitationHandler := handler.NewInvitationHandler(invitationUseCase)
+
+	// Public keys for services verifying access tokens
+	jwksHandler := handler.NewJWKSHandler(tokenManager)
+	s.App.Get("/.well-known/jwks.json", jwksHandler.GetKeys)
 
 	// Public routes
 	auth := s.App.Group("/api/auth")
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
-	auth.Put("/refresh", authHandler.RefreshToken)
-	auth.Delete("/logout", authHandler.Logout)
+	auth.Post("/login/mfa", authHandler.VerifyMFALogin)
+	auth.Post("/login/mfa/enroll", authHandler.BeginMFAEnrollment)
+	auth.Post("/login/mfa/activate", authHandler.CompleteMFAEnrollment)
+	if cookie := refreshCookieConfig(); cookie.Enabled {
+		// The browser sends the cookie on its own, so these need CSRF protection
+		csrf := middleware.CSRFMiddleware(cookie.CSRFName)
+		auth.Put("/refresh", csrf, authHandler.RefreshToken)
+		auth.Delete("/logout", csrf, authHandler.Logout)
+	} else {
+		auth.Put("/refresh", authHandler.RefreshToken)
+		auth.Delete("/logout", authHandler.Logout)
+	}
+	auth.Post("/password/reset", passwordHandler.ResetPassword)
+	auth.Post("/invitations/accept", invitationHandler.Accept)
 
 	// Protected routes
-	authMiddleware := middleware.AuthMiddleware(tokenManager, userRepo)
+	authMiddleware := s.authMiddleware(tokenManager)
+	// Credentials and sessions are only changed by the user in person
+	ownerOnly := middleware.DenyImpersonation()
 	auth.Get("/profile", authMiddleware, authHandler.GetProfile)
+	auth.Get("/sessions", authMiddleware, authHandler.ListSessions)
+	auth.Delete("/sessions", authMiddleware, ownerOnly, authHandler.RevokeOtherSessions)
+	auth.Delete("/sessions/:id", authMiddleware, ownerOnly, authHandler.RevokeSession)
+	auth.Put("/password", authMiddleware, ownerOnly, passwordHandler.ChangePassword)
+	auth.Post("/password/reset-requests", authMiddleware, middleware.RequirePermission(domain.PermissionUserManage), passwordHandler.RequestReset)
+	auth.Delete("/lockouts/:username", authMiddleware, middleware.RequirePermission(domain.PermissionUserManage), authHandler.UnlockAccount)
+	auth.Get("/mfa", authMiddleware, mfaHandler.Status)
+	auth.Post("/mfa/enroll", authMiddleware, ownerOnly, mfaHandler.Enroll)
+	auth.Post("/mfa/activate", authMiddleware, ownerOnly, mfaHandler.Activate)
+	auth.Delete("/mfa", authMiddleware, ownerOnly, mfaHandler.Disable)
+	auth.Post("/mfa/recovery-codes", authMiddleware, ownerOnly, mfaHandler.RegenerateRecoveryCodes)
+
+	// Invitation management
+	invitations := s.App.Group("/api/invitations", authMiddleware, middleware.RequirePermission(domain.PermissionUserManage))
+	invitations.Get("/", invitationHandler.List)
+	invitations.Post("/", invitationHandler.Create)
+	invitations.Delete("/:id", invitationHandler.Revoke)
+}
+
+// SetupRoleRoutes sets up role and permission management routes
+func (s *FiberServer) SetupRoleRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	userRepo := repository.NewUserRepository(s.db.GetDB())
+	roleRepo := repository.NewRoleRepository(s.db.GetDB())
+
+	roleUseCase := usecase.NewRoleUseCase(roleRepo, userRepo, s.principals)
+	roleHandler := handler.NewRoleHandler(roleUseCase)
+
+	authMiddleware := s.authMiddleware(tokenManager)
+	canManageRoles := middleware.RequirePermission(domain.PermissionRoleManage)
+
+	roles := s.App.Group("/api/roles", authMiddleware, canManageRoles)
+	roles.Get("/", roleHandler.ListRoles)
+	roles.Post("/", roleHandler.CreateRole)
+	roles.Get("/:name", roleHandler.GetRole)
+	roles.Put("/:name", roleHandler.UpdateRole)
+	roles.Delete("/:name", roleHandler.DeleteRole)
+
+	s.App.Get("/api/permissions", authMiddleware, canManageRoles, roleHandler.ListPermissions)
+
+	s.App.Get("/api/users/:id/roles", authMiddleware, canManageRoles, roleHandler.GetUserRoles)
+	s.App.Put("/api/users/:id/roles", authMiddleware, canManageRoles, roleHandler.SetUserRoles)
+}
+
+// SetupApprovalRoutes sets up approval limit routes
+func (s *FiberServer) SetupApprovalRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	limitRepo := repository.NewApprovalLimitRepository(s.db.GetDB())
+
+	approvalUseCase := usecase.NewApprovalUseCase(limitRepo, usecase.ApprovalConfig{
+		DefaultCurrency: envString("APPROVAL_DEFAULT_CURRENCY", "IDR"),
+	})
+	approvalHandler := handler.NewApprovalHandler(approvalUseCase)
+
+	authMiddleware := s.authMiddleware(tokenManager)
+
+	// Approvals and limits are decided by the user in person, never on their behalf
+	ownerOnly := middleware.DenyImpersonation()
+
+	s.App.Post("/api/approvals/check", authMiddleware, ownerOnly, approvalHandler.CheckApproval)
+
+	limits := s.App.Group("/api/approval-limits", authMiddleware, ownerOnly, middleware.RequirePermission(domain.PermissionApprovalManage))
+	limits.Get("/", approvalHandler.ListLimits)
+	limits.Post("/", approvalHandler.CreateLimit)
+	limits.Get("/:id", approvalHandler.GetLimit)
+	limits.Put("/:id", approvalHandler.UpdateLimit)
+	limits.Delete("/:id", approvalHandler.DeleteLimit)
+}
+
+// SetupUserRoutes sets up admin user management routes
+func (s *FiberServer) SetupUserRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+	passwordHash := security.NewPasswordHash(passwordHashConfig())
+
+	userRepo := repository.NewUserRepository(s.db.GetDB())
+	authRepo := repository.NewAuthenticationRepository(s.db.GetDB())
+	roleRepo := repository.NewRoleRepository(s.db.GetDB())
+
+	userUseCase := usecase.NewUserUseCase(userRepo, authRepo, roleRepo, passwordHash, s.passwordPolicy(passwordHash), s.revocations, s.principals)
+	userHandler := handler.NewUserHandler(userUseCase)
+
+	authMiddleware := s.authMiddleware(tokenManager)
+	canRead := middleware.RequirePermission(domain.PermissionUserRead)
+	canManage := middleware.RequirePermission(domain.PermissionUserManage)
+
+	// Middleware is added per route since /api/users/:id/roles and /api/users/:id/warehouses
+	// are set up with the role and warehouse routes
+	users := s.App.Group("/api/users")
+	users.Get("/", authMiddleware, canRead, userHandler.List)
+	users.Get("/:id", authMiddleware, canRead, userHandler.Get)
+	users.Put("/:id", authMiddleware, canManage, userHandler.Update)
+	users.Put("/:id/password", authMiddleware, canManage, userHandler.SetPassword)
+	users.Post("/:id/suspend", authMiddleware, canManage, userHandler.Suspend)
+	users.Post("/:id/deactivate", authMiddleware, canManage, userHandler.Deactivate)
+	users.Post("/:id/activate", authMiddleware, canManage, userHandler.Activate)
+	users.Delete("/:id", authMiddleware, canManage, userHandler.Delete)
+}
+
+// SetupImpersonationRoutes sets up admin impersonation routes
+func (s *FiberServer) SetupImpersonationRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	userRepo := repository.NewUserRepository(s.db.GetDB())
+	roleRepo := repository.NewRoleRepository(s.db.GetDB())
+
+	impersonationUseCase := usecase.NewImpersonationUseCase(userRepo, roleRepo, tokenManager, s.revocations, s.audit, usecase.ImpersonationConfig{
+		TokenAge: envSeconds("IMPERSONATION_TOKEN_AGE", 15*time.Minute),
+	})
+	impersonationHandler := handler.NewImpersonationHandler(impersonationUseCase)
+
+	authMiddleware := s.authMiddleware(tokenManager)
+
+	s.App.Post("/api/users/:id/impersonate", authMiddleware, middleware.RequirePermission(domain.PermissionUserImpersonate), impersonationHandler.Start)
+	s.App.Delete("/api/auth/impersonation", authMiddleware, impersonationHandler.End)
+}
+
+// SetupAPIKeyRoutes sets up API key management routes
+func (s *FiberServer) SetupAPIKeyRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	roleRepo := repository.NewRoleRepository(s.db.GetDB())
+	apiKeyRepo := repository.NewAPIKeyRepository(s.db.GetDB())
+
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, roleRepo)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUseCase)
+
+	warehouseUseCase := usecase.NewWarehouseUseCase(
+		repository.NewWarehouseRepository(s.db.GetDB()),
+		repository.NewLocationRepository(s.db.GetDB()),
+		repository.NewUserRepository(s.db.GetDB()),
+		apiKeyRepo,
+	)
+	warehouseHandler := handler.NewWarehouseHandler(warehouseUseCase)
+
+	canManageWarehouses := middleware.RequirePermission(domain.PermissionWarehouseManage)
+
+	apiKeys := s.App.Group("/api/api-keys", s.authMiddleware(tokenManager), middleware.RequirePermission(domain.PermissionAPIKeyManage))
+	apiKeys.Get("/", apiKeyHandler.List)
+	apiKeys.Post("/", apiKeyHandler.Create)
+	apiKeys.Delete("/:id", apiKeyHandler.Revoke)
+	apiKeys.Get("/:id/warehouses", canManageWarehouses, warehouseHandler.GetAPIKeyWarehouses)
+	apiKeys.Put("/:id/warehouses", canManageWarehouses, warehouseHandler.SetAPIKeyWarehouses)
+}
+
+// SetupOIDCRoutes sets up single sign-on routes when an identity provider is configured
+func (s *FiberServer) SetupOIDCRoutes() {
+	issuerURL := envString("OIDC_ISSUER_URL", "")
+	if issuerURL == "" {
+		return
+	}
+
+	tokenManager := security.NewJWTTokenManager()
+
+	provider := security.NewOIDCProvider(security.OIDCConfig{
+		IssuerURL:    issuerURL,
+		ClientID:     envString("OIDC_CLIENT_ID", ""),
+		ClientSecret: envString("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  envString("OIDC_REDIRECT_URL", "http://localhost:3000/sso/callback"),
+		Scopes:       envList("OIDC_SCOPES"),
+		GroupsClaim:  envString("OIDC_GROUPS_CLAIM", "groups"),
+	})
+
+	oidcRepo := repository.NewOIDCRepository(s.db.GetDB())
+	userRepo := repository.NewUserRepository(s.db.GetDB())
+	roleRepo := repository.NewRoleRepository(s.db.GetDB())
+	authRepo := repository.NewAuthenticationRepository(s.db.GetDB())
+	mfaRepo := repository.NewMFARepository(s.db.GetDB())
+
+	mfaUseCase := usecase.NewMFAUseCase(userRepo, mfaRepo, mfaConfig())
+
+	stateAge := envSeconds("OIDC_STATE_AGE", 10*time.Minute)
+
+	mfaMethods := envList("OIDC_MFA_AMR_VALUES")
+	if len(mfaMethods) == 0 {
+		mfaMethods = []string{"mfa"}
+	}
+
+	oidcUseCase := usecase.NewOIDCUseCase(provider, oidcRepo, userRepo, roleRepo, authRepo, tokenManager, s.principals, mfaUseCase, s.audit, usecase.OIDCConfig{
+		GroupRoles:  oidcGroupRoles(),
+		DefaultRole: domain.UserRole(envString("OIDC_DEFAULT_ROLE", string(domain.RoleStaff))),
+		SyncRoles:   envBool("OIDC_SYNC_ROLES", true),
+		StateAge:    stateAge,
+		MFAMethods:  mfaMethods,
+		MFAContexts: envList("OIDC_MFA_ACR_VALUES"),
+	})
+	oidcHandler := handler.NewOIDCHandler(oidcUseCase, refreshCookieConfig(), oidcStateCookieConfig(stateAge))
+
+	oidc := s.App.Group("/api/auth/oidc")
+	oidc.Post("/authorize", oidcHandler.Authorize)
+	oidc.Post("/callback", oidcHandler.Callback)
+}
+
+// SetupAuditRoutes sets up security audit log routes
+func (s *FiberServer) SetupAuditRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	auditHandler := handler.NewAuditHandler(s.audit)
+
+	audit := s.App.Group("/api/audit-events", s.authMiddleware(tokenManager), middleware.RequirePermission(domain.PermissionAuditRead))
+	audit.Get("/", auditHandler.List)
+	audit.Get("/export", auditHandler.Export)
+}
+
+// SetupMaintenanceRoutes sets up routes reporting on background jobs
+func (s *FiberServer) SetupMaintenanceRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	tokenJanitorHandler := handler.NewTokenJanitorHandler(s.tokenJanitor)
+
+	maintenance := s.App.Group("/api/maintenance", s.authMiddleware(tokenManager), middleware.RequirePermission(domain.PermissionUserManage))
+	maintenance.Get("/token-cleanup", tokenJanitorHandler.Metrics)
+}
+
+// SetupProductRoutes sets up product catalog, category and unit of measure routes
+func (s *FiberServer) SetupProductRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	productRepo := repository.NewProductRepository(s.db.GetDB())
+	categoryRepo := repository.NewCategoryRepository(s.db.GetDB())
+	uomRepo := repository.NewUoMRepository(s.db.GetDB())
+	stockRepo := repository.NewStockRepository(s.db.GetDB())
+
+	productUseCase := usecase.NewProductUseCase(productRepo, categoryRepo, uomRepo, stockRepo)
+	productHandler := handler.NewProductHandler(productUseCase)
+
+	categoryUseCase := usecase.NewCategoryUseCase(categoryRepo)
+	categoryHandler := handler.NewCategoryHandler(categoryUseCase)
+
+	uomUseCase := usecase.NewUoMUseCase(uomRepo, productRepo)
+	uomHandler := handler.NewUoMHandler(uomUseCase)
+
+	authMiddleware := s.authMiddleware(tokenManager)
+
+	canRead := middleware.RequirePermission(domain.PermissionProductRead)
+	canWrite := middleware.RequirePermission(domain.PermissionProductWrite)
+
+	products := s.App.Group("/api/products", authMiddleware)
+	products.Get("/", canRead, productHandler.List)
+	products.Get("/:id", canRead, productHandler.Get)
+	products.Post("/", canWrite, productHandler.Create)
+	products.Put("/:id", canWrite, productHandler.Update)
+	products.Delete("/:id", canWrite, productHandler.Delete)
+	products.Get("/:id/uoms", canRead, uomHandler.ListProductUnits)
+	products.Post("/:id/uoms/convert", canRead, uomHandler.Convert)
+	products.Put("/:id/uoms/:uom", canWrite, uomHandler.SetProductUnit)
+	products.Delete("/:id/uoms/:uom", canWrite, uomHandler.DeleteProductUnit)
+
+	categories := s.App.Group("/api/categories", authMiddleware)
+	categories.Get("/", canRead, categoryHandler.List)
+	categories.Get("/tree", canRead, categoryHandler.Tree)
+	categories.Get("/:id", canRead, categoryHandler.Get)
+	categories.Post("/", canWrite, categoryHandler.Create)
+	categories.Put("/:id", canWrite, categoryHandler.Update)
+	categories.Put("/:id/parent", canWrite, categoryHandler.Move)
+	categories.Delete("/:id", canWrite, categoryHandler.Delete)
+
+	uoms := s.App.Group("/api/uoms", authMiddleware)
+	uoms.Get("/", canRead, uomHandler.ListUnits)
+	uoms.Get("/:code", canRead, uomHandler.GetUnit)
+	uoms.Post("/", canWrite, uomHandler.CreateUnit)
+	uoms.Put("/:code", canWrite, uomHandler.UpdateUnit)
+	uoms.Delete("/:code", canWrite, uomHandler.DeleteUnit)
+}
+
+// SetupWarehouseRoutes sets up warehouse, location and warehouse assignment routes
+func (s *FiberServer) SetupWarehouseRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	warehouseRepo := repository.NewWarehouseRepository(s.db.GetDB())
+	locationRepo := repository.NewLocationRepository(s.db.GetDB())
+	userRepo := repository.NewUserRepository(s.db.GetDB())
+	apiKeyRepo := repository.NewAPIKeyRepository(s.db.GetDB())
+
+	warehouseUseCase := usecase.NewWarehouseUseCase(warehouseRepo, locationRepo, userRepo, apiKeyRepo)
+	warehouseHandler := handler.NewWarehouseHandler(warehouseUseCase)
+
+	locationUseCase := usecase.NewLocationUseCase(locationRepo, warehouseRepo)
+	locationHandler := handler.NewLocationHandler(locationUseCase)
+
+	authMiddleware := s.authMiddleware(tokenManager)
+
+	canManage := middleware.RequirePermission(domain.PermissionWarehouseManage)
+
+	// Lists only hold the warehouses of the user, single records are checked by WarehouseScope
+	warehouses := s.App.Group("/api/warehouses", authMiddleware)
+	warehouses.Get("/", warehouseHandler.List)
+	warehouses.Get("/:id", middleware.WarehouseScope(warehouseUseCase, middleware.WarehouseFromParam, "id"), warehouseHandler.Get)
+	warehouses.Post("/", canManage, warehouseHandler.Create)
+	warehouses.Put("/:id", canManage, warehouseHandler.Update)
+	warehouses.Delete("/:id", canManage, warehouseHandler.Delete)
+	warehouses.Get("/:id/users", canManage, warehouseHandler.ListUsers)
+
+	locations := s.App.Group("/api/locations", authMiddleware)
+	locations.Get("/", middleware.WarehouseScope(warehouseUseCase, middleware.WarehouseFromQuery, "warehouse_id"), locationHandler.List)
+	locations.Get("/:id", middleware.WarehouseScope(warehouseUseCase, middleware.LocationFromParam, "id"), locationHandler.Get)
+	locations.Post("/", canManage, locationHandler.Create)
+	locations.Put("/:id", canManage, locationHandler.Update)
+	locations.Delete("/:id", canManage, locationHandler.Delete)
+
+	s.App.Get("/api/users/:id/warehouses", authMiddleware, canManage, warehouseHandler.GetUserWarehouses)
+	s.App.Put("/api/users/:id/warehouses", authMiddleware, canManage, warehouseHandler.SetUserWarehouses)
+}
+
+// SetupStockRoutes sets up stock ledger routes
+func (s *FiberServer) SetupStockRoutes() {
+	tokenManager := security.NewJWTTokenManager()
+
+	stockRepo := repository.NewStockRepository(s.db.GetDB())
+	locationRepo := repository.NewLocationRepository(s.db.GetDB())
+	warehouseRepo := repository.NewWarehouseRepository(s.db.GetDB())
+	userRepo := repository.NewUserRepository(s.db.GetDB())
+	productRepo := repository.NewProductRepository(s.db.GetDB())
+	uomRepo := repository.NewUoMRepository(s.db.GetDB())
+	apiKeyRepo := repository.NewAPIKeyRepository(s.db.GetDB())
+
+	warehouseUseCase := usecase.NewWarehouseUseCase(warehouseRepo, locationRepo, userRepo, apiKeyRepo)
+	uomUseCase := usecase.NewUoMUseCase(uomRepo, productRepo)
+
+	stockUseCase := usecase.NewStockUseCase(
+		repository.NewUnitOfWork(s.db.GetDB()),
+		stockRepo,
+		locationRepo,
+		warehouseUseCase,
+		uomUseCase,
+	)
+	stockHandler := handler.NewStockHandler(stockUseCase)
+
+	authMiddleware := s.authMiddleware(tokenManager)
+
+	canRead := middleware.RequirePermission(domain.PermissionStockRead)
+	canPost := middleware.RequirePermission(domain.PermissionStockPost)
+	warehouseScope := middleware.WarehouseScope(warehouseUseCase, middleware.WarehouseFromQuery, "warehouse_id")
+
+	// Postings check the warehouse of every line in the use case
+	stock := s.App.Group("/api/stock", authMiddleware)
+	stock.Get("/balances", canRead, warehouseScope, stockHandler.ListBalances)
+	stock.Get("/movements", canRead, warehouseScope, stockHandler.ListMovements)
+	stock.Post("/postings", canPost, stockHandler.Post)
+	stock.Post("/postings/:id/reverse", canPost, stockHandler.Reverse)
 }
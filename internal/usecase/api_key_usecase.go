@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// apiKeyPrefix starts every key so leaked keys are easy to recognise
+	apiKeyPrefix = "sfk"
+
+	// apiKeyUsageInterval limits how often last_used_at is written
+	apiKeyUsageInterval = time.Minute
+)
+
+// APIKeyUseCase defines API key use case interface
+type APIKeyUseCase interface {
+	Create(ctx context.Context, req *CreateAPIKeyRequest, createdBy string) (*APIKeyResponse, error)
+	List(ctx context.Context) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	// Authenticate resolves a raw key into the principal it acts as
+	Authenticate(ctx context.Context, rawKey string) (*domain.User, error)
+}
+
+// CreateAPIKeyRequest represents API key create request
+type CreateAPIKeyRequest struct {
+	Name   string              `json:"name"`
+	Role   domain.UserRole     `json:"role"`
+	Scopes []domain.Permission `json:"scopes"`
+	// ExpiresIn is the lifetime of the key in seconds, the key never expires when zero
+	ExpiresIn int `json:"expires_in"`
+}
+
+// APIKeyResponse represents a created API key.
+// The key is only returned once, it cannot be recovered later.
+type APIKeyResponse struct {
+	*domain.APIKey
+	Key string `json:"key"`
+}
+
+type apiKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+	roleRepo   repository.RoleRepository
+}
+
+// NewAPIKeyUseCase creates a new API key use case
+func NewAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, roleRepo repository.RoleRepository) APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+		roleRepo:   roleRepo,
+	}
+}
+
+func (u *apiKeyUseCase) Create(ctx context.Context, req *CreateAPIKeyRequest, createdBy string) (*APIKeyResponse, error) {
+	role, err := u.roleRepo.GetByName(ctx, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// A key can only be narrowed down, never get more than its role
+	for _, scope := range req.Scopes {
+		if !hasPermission(role.Permissions, scope) {
+			return nil, domain.ErrAPIKeyScopeInvalid
+		}
+	}
+
+	prefix := make([]byte, 4)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+
+	secret, err := security.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &domain.APIKey{
+		ID:        "apikey-" + uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    hex.EncodeToString(prefix),
+		Role:      req.Role,
+		Scopes:    req.Scopes,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	if key.Scopes == nil {
+		key.Scopes = []domain.Permission{}
+	}
+
+	if req.ExpiresIn > 0 {
+		expiresAt := key.CreatedAt.Add(time.Duration(req.ExpiresIn) * time.Second)
+		key.ExpiresAt = &expiresAt
+	}
+
+	rawKey := apiKeyPrefix + "_" + key.Prefix + "_" + secret
+	key.KeyHash = security.HashToken(rawKey)
+
+	err = u.apiKeyRepo.Create(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIKeyResponse{
+		APIKey: key,
+		Key:    rawKey,
+	}, nil
+}
+
+func (u *apiKeyUseCase) List(ctx context.Context) ([]*domain.APIKey, error) {
+	return u.apiKeyRepo.List(ctx)
+}
+
+func (u *apiKeyUseCase) Revoke(ctx context.Context, id string) error {
+	return u.apiKeyRepo.Revoke(ctx, id)
+}
+
+func (u *apiKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*domain.User, error) {
+	// Keys look like sfk_<prefix>_<secret>
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix {
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	key, err := u.apiKeyRepo.GetByPrefix(ctx, parts[1])
+	if err != nil {
+		if err == domain.ErrAPIKeyNotFound {
+			return nil, domain.ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+
+	hash := security.HashToken(rawKey)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(key.KeyHash)) != 1 {
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	now := time.Now()
+	if !key.IsUsable(now) {
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	// Keys of a deleted role grant nothing
+	role, err := u.roleRepo.GetByName(ctx, key.Role)
+	if err != nil {
+		if err == domain.ErrRoleNotFound {
+			return nil, domain.ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+
+	// Scanners call often, so usage is only recorded once per interval
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyUsageInterval {
+		if err := u.apiKeyRepo.UpdateLastUsed(ctx, key.ID, now); err != nil {
+			log.Printf("Failed to record usage of API key %s: %v", key.Prefix, err)
+		}
+	}
+
+	return key.Principal(role.Permissions), nil
+}
+
+// hasPermission checks if permission is part of permissions
+func hasPermission(permissions []domain.Permission, permission domain.Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeAPIKeyRepository struct {
+	repository.APIKeyRepository
+	keys []*domain.APIKey
+}
+
+func (r *fakeAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	return nil
+}
+
+type fakeRoleRepository struct {
+	repository.RoleRepository
+	roles []*domain.Role
+}
+
+func (r *fakeRoleRepository) GetByName(ctx context.Context, name domain.UserRole) (*domain.Role, error) {
+	for _, role := range r.roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, domain.ErrRoleNotFound
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	const rawKey = "sfk_abcd1234_secret"
+	expired := time.Now().Add(-time.Minute)
+
+	newKey := func(role domain.UserRole) *domain.APIKey {
+		return &domain.APIKey{ID: "apikey-1", Prefix: "abcd1234", KeyHash: security.HashToken(rawKey), Role: role}
+	}
+	roleRepo := &fakeRoleRepository{roles: []*domain.Role{
+		{Name: "operator", Permissions: []domain.Permission{domain.PermissionStockPost}},
+	}}
+
+	tests := []struct {
+		name   string
+		key    *domain.APIKey
+		rawKey string
+		err    error
+	}{
+		{name: "valid key", key: newKey("operator"), rawKey: rawKey},
+		{name: "wrong secret", key: newKey("operator"), rawKey: "sfk_abcd1234_other", err: domain.ErrAPIKeyInvalid},
+		{name: "unknown prefix", key: newKey("operator"), rawKey: "sfk_ffff0000_secret", err: domain.ErrAPIKeyInvalid},
+		{name: "malformed key", key: newKey("operator"), rawKey: "abcd1234", err: domain.ErrAPIKeyInvalid},
+		{
+			name:   "expired key",
+			key:    &domain.APIKey{ID: "apikey-1", Prefix: "abcd1234", KeyHash: security.HashToken(rawKey), Role: "operator", ExpiresAt: &expired},
+			rawKey: rawKey,
+			err:    domain.ErrAPIKeyInvalid,
+		},
+		{name: "deleted role", key: newKey("scanner"), rawKey: rawKey, err: domain.ErrAPIKeyInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeyUseCase := NewAPIKeyUseCase(&fakeAPIKeyRepository{keys: []*domain.APIKey{tt.key}}, roleRepo)
+
+			principal, err := apiKeyUseCase.Authenticate(context.Background(), tt.rawKey)
+			if err != tt.err {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && principal.APIKeyID != tt.key.ID {
+				t.Errorf("Authenticate() = %+v, want the principal of %s", principal, tt.key.ID)
+			}
+		})
+	}
+}
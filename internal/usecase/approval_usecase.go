@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalUseCase defines approval limit use case interface
+type ApprovalUseCase interface {
+	Decide(ctx context.Context, user *domain.User, req *ApprovalRequest) (*domain.ApprovalDecision, error)
+	ListLimits(ctx context.Context, filter repository.ApprovalLimitFilter) ([]*domain.ApprovalLimit, error)
+	GetLimit(ctx context.Context, id string) (*domain.ApprovalLimit, error)
+	CreateLimit(ctx context.Context, req *ApprovalLimitRequest) (*domain.ApprovalLimit, error)
+	UpdateLimit(ctx context.Context, id string, req *UpdateApprovalLimitRequest) (*domain.ApprovalLimit, error)
+	DeleteLimit(ctx context.Context, id string) error
+}
+
+// ApprovalConfig holds approval settings
+type ApprovalConfig struct {
+	DefaultCurrency string
+}
+
+// ApprovalRequest represents an amount to be approved
+type ApprovalRequest struct {
+	Amount       float64 `json:"amount"`
+	Currency     string  `json:"currency"`
+	DocumentType string  `json:"document_type"`
+}
+
+// ApprovalLimitRequest represents approval limit create request.
+// Exactly one of Role and UserID must be set; a null max_amount means unlimited.
+type ApprovalLimitRequest struct {
+	Role         domain.UserRole `json:"role"`
+	UserID       string          `json:"user_id"`
+	Currency     string          `json:"currency"`
+	DocumentType string          `json:"document_type"`
+	MaxAmount    *float64        `json:"max_amount"`
+}
+
+// UpdateApprovalLimitRequest represents approval limit update request
+type UpdateApprovalLimitRequest struct {
+	MaxAmount *float64 `json:"max_amount"`
+}
+
+type approvalUseCase struct {
+	limitRepo repository.ApprovalLimitRepository
+	config    ApprovalConfig
+}
+
+// NewApprovalUseCase creates a new approval use case
+func NewApprovalUseCase(limitRepo repository.ApprovalLimitRepository, config ApprovalConfig) ApprovalUseCase {
+	config.DefaultCurrency = strings.ToUpper(config.DefaultCurrency)
+	return &approvalUseCase{
+		limitRepo: limitRepo,
+		config:    config,
+	}
+}
+
+// Decide checks amount against the most specific limit of the user.
+// A user override wins over role limits; when several roles have a limit
+// the most permissive one applies. Without any limit the approval is denied.
+func (u *approvalUseCase) Decide(ctx context.Context, user *domain.User, req *ApprovalRequest) (*domain.ApprovalDecision, error) {
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		currency = u.config.DefaultCurrency
+	}
+	documentType := strings.TrimSpace(req.DocumentType)
+
+	roles := user.Roles
+	if len(roles) == 0 {
+		roles = []domain.UserRole{user.Role}
+	}
+
+	limits, err := u.limitRepo.FindApplicable(ctx, user.ID, roles, currency, documentType)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := selectApprovalLimit(limits)
+
+	return &domain.ApprovalDecision{
+		Allowed:      limit != nil && limit.Allows(req.Amount),
+		Amount:       req.Amount,
+		Currency:     currency,
+		DocumentType: documentType,
+		Limit:        limit,
+	}, nil
+}
+
+// selectApprovalLimit picks the applicable limit out of the matching ones
+func selectApprovalLimit(limits []*domain.ApprovalLimit) *domain.ApprovalLimit {
+	// Most specific limit per role, or per user for overrides
+	best := map[string]*domain.ApprovalLimit{}
+	var override *domain.ApprovalLimit
+	for _, limit := range limits {
+		if limit.UserID != "" {
+			if override == nil || limit.Specificity() > override.Specificity() {
+				override = limit
+			}
+			continue
+		}
+
+		current, ok := best[string(limit.Role)]
+		if !ok || limit.Specificity() > current.Specificity() {
+			best[string(limit.Role)] = limit
+		}
+	}
+
+	if override != nil {
+		return override
+	}
+
+	var selected *domain.ApprovalLimit
+	for _, limit := range best {
+		if selected == nil || morePermissive(limit, selected) {
+			selected = limit
+		}
+	}
+	return selected
+}
+
+// morePermissive checks if a allows a higher amount than b, preferring the
+// more specific limit on a tie so the choice is deterministic
+func morePermissive(a, b *domain.ApprovalLimit) bool {
+	switch {
+	case a.IsUnlimited() && b.IsUnlimited():
+		return a.Specificity() > b.Specificity() || (a.Specificity() == b.Specificity() && a.Role < b.Role)
+	case a.IsUnlimited():
+		return true
+	case b.IsUnlimited():
+		return false
+	case *a.MaxAmount != *b.MaxAmount:
+		return *a.MaxAmount > *b.MaxAmount
+	}
+	return a.Specificity() > b.Specificity() || (a.Specificity() == b.Specificity() && a.Role < b.Role)
+}
+
+func (u *approvalUseCase) ListLimits(ctx context.Context, filter repository.ApprovalLimitFilter) ([]*domain.ApprovalLimit, error) {
+	return u.limitRepo.List(ctx, filter)
+}
+
+func (u *approvalUseCase) GetLimit(ctx context.Context, id string) (*domain.ApprovalLimit, error) {
+	return u.limitRepo.GetByID(ctx, id)
+}
+
+func (u *approvalUseCase) CreateLimit(ctx context.Context, req *ApprovalLimitRequest) (*domain.ApprovalLimit, error) {
+	if (req.Role == "") == (req.UserID == "") {
+		return nil, domain.ErrApprovalLimitInvalid
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency != "" && len(currency) != 3 {
+		return nil, domain.ErrBadRequest
+	}
+
+	if req.MaxAmount != nil && *req.MaxAmount < 0 {
+		return nil, domain.ErrBadRequest
+	}
+
+	now := time.Now()
+	limit := &domain.ApprovalLimit{
+		ID:           "limit-" + uuid.New().String(),
+		Role:         req.Role,
+		UserID:       req.UserID,
+		Currency:     currency,
+		DocumentType: strings.TrimSpace(req.DocumentType),
+		MaxAmount:    req.MaxAmount,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	err := u.limitRepo.Create(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+func (u *approvalUseCase) UpdateLimit(ctx context.Context, id string, req *UpdateApprovalLimitRequest) (*domain.ApprovalLimit, error) {
+	if req.MaxAmount != nil && *req.MaxAmount < 0 {
+		return nil, domain.ErrBadRequest
+	}
+
+	limit, err := u.limitRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	limit.MaxAmount = req.MaxAmount
+	err = u.limitRepo.Update(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+func (u *approvalUseCase) DeleteLimit(ctx context.Context, id string) error {
+	return u.limitRepo.Delete(ctx, id)
+}
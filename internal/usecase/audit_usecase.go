@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditRecorder writes security audit events.
+// Recording never fails the audited operation, errors are only logged.
+type AuditRecorder interface {
+	Record(ctx context.Context, event *domain.AuditEvent)
+}
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+// AuditUseCase defines audit log use case interface
+type AuditUseCase interface {
+	AuditRecorder
+	List(ctx context.Context, req *ListAuditEventsRequest) (*AuditEventListResponse, error)
+	// Export calls fn for every matching event, oldest first, ignoring the page
+	Export(ctx context.Context, req *ListAuditEventsRequest, fn func(event *domain.AuditEvent) error) error
+}
+
+// ListAuditEventsRequest represents audit log query
+type ListAuditEventsRequest struct {
+	Actor    string
+	Action   domain.AuditAction
+	Outcome  domain.AuditOutcome
+	From     time.Time
+	To       time.Time
+	Page     int
+	PageSize int
+}
+
+// AuditEventListResponse represents one page of audit events
+type AuditEventListResponse struct {
+	Events     []*domain.AuditEvent `json:"events"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+type auditUseCase struct {
+	auditRepo repository.AuditRepository
+}
+
+// NewAuditUseCase creates a new audit log use case
+func NewAuditUseCase(auditRepo repository.AuditRepository) AuditUseCase {
+	return &auditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+func (u *auditUseCase) Record(ctx context.Context, event *domain.AuditEvent) {
+	event.ID = "audit-" + uuid.New().String()
+	event.CreatedAt = time.Now()
+
+	if err := u.auditRepo.Create(ctx, event); err != nil {
+		log.Printf("Failed to record audit event '%s' of '%s': %v\n", event.Action, event.ActorID, err)
+	}
+}
+
+func (u *auditUseCase) List(ctx context.Context, req *ListAuditEventsRequest) (*AuditEventListResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = defaultAuditPageSize
+	}
+	if pageSize > maxAuditPageSize {
+		pageSize = maxAuditPageSize
+	}
+
+	filter := auditFilter(req)
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+
+	events, total, err := u.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditEventListResponse{
+		Events: events,
+		Pagination: Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (u *auditUseCase) Export(ctx context.Context, req *ListAuditEventsRequest, fn func(event *domain.AuditEvent) error) error {
+	return u.auditRepo.ForEach(ctx, auditFilter(req), fn)
+}
+
+func auditFilter(req *ListAuditEventsRequest) repository.AuditFilter {
+	return repository.AuditFilter{
+		Actor:   strings.TrimSpace(req.Actor),
+		Action:  req.Action,
+		Outcome: req.Outcome,
+		From:    req.From,
+		To:      req.To,
+	}
+}
+
+// auditEvent creates an event of action from client.
+// A failed operation is recorded with the error as reason.
+func auditEvent(action domain.AuditAction, user *domain.User, client domain.ClientInfo, err error) *domain.AuditEvent {
+	event := &domain.AuditEvent{
+		Action:    action,
+		Outcome:   domain.AuditOutcomeSuccess,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Metadata:  map[string]any{},
+	}
+
+	if user != nil {
+		event.ActorID = user.ID
+		event.ActorUsername = user.Username
+	}
+
+	if err != nil {
+		event.Outcome = domain.AuditOutcomeFailure
+		event.Metadata["reason"] = err.Error()
+	}
+
+	return event
+}
@@ -5,42 +5,74 @@ import (
 	"api-stockflow/internal/repository"
 	"api-stockflow/internal/security"
 	"context"
+	"log"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
 // AuthUseCase defines authentication use case interface
 type AuthUseCase interface {
-	Login(ctx context.Context, username, password string) (*LoginResponse, error)
-	RefreshToken(ctx context.Context, refreshToken string) (*RefreshTokenResponse, error)
-	Logout(ctx context.Context, refreshToken string) error
-	Register(ctx context.Context, req *RegisterRequest) error
+	Login(ctx context.Context, username, password string, client domain.ClientInfo) (*LoginResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*RefreshTokenResponse, error)
+	Logout(ctx context.Context, refreshToken string, client domain.ClientInfo) error
+	Register(ctx context.Context, req *RegisterRequest, client domain.ClientInfo) error
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]*domain.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) (int64, error)
+	UnlockAccount(ctx context.Context, username string) error
+	VerifyMFALogin(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*LoginResponse, error)
+	BeginMFAEnrollment(ctx context.Context, mfaToken string) (*domain.MFAEnrollment, error)
+	CompleteMFAEnrollment(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*MFAEnrollmentResponse, error)
 }
 
-// LoginResponse represents login response
+// LoginResponse represents login response.
+// When a second factor is needed only the MFA fields are set.
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken           string `json:"access_token,omitempty"`
+	RefreshToken          string `json:"refresh_token,omitempty"`
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty"`
+}
+
+// MFAEnrollmentResponse represents the result of enrolling during login
+type MFAEnrollmentResponse struct {
+	*LoginResponse
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 // RefreshTokenResponse represents refresh token response
 type RefreshTokenResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
-// RegisterRequest represents user registration request
+// RegisterRequest represents user self-registration request.
+// The role cannot be chosen, self-registered users are always staff.
 type RegisterRequest struct {
-	Username string           `json:"username"`
-	Password string           `json:"password"`
-	Fullname string           `json:"fullname"`
-	Role     domain.UserRole  `json:"role"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Fullname string `json:"fullname"`
+}
+
+// AuthConfig holds authentication settings
+type AuthConfig struct {
+	AllowSelfRegistration bool
 }
 
 type authUseCase struct {
-	userRepo           repository.UserRepository
-	authRepo           repository.AuthenticationRepository
-	tokenManager       security.TokenManager
-	passwordHash       security.PasswordHash
+	userRepo       repository.UserRepository
+	authRepo       repository.AuthenticationRepository
+	tokenManager   security.TokenManager
+	passwordHash   security.PasswordHash
+	passwordPolicy PasswordPolicy
+	loginLimiter   LoginLimiter
+	mfaUseCase     MFAUseCase
+	revocations    security.RevocationStore
+	audit          AuditRecorder
+	config         AuthConfig
 }
 
 // NewAuthUseCase creates a new authentication use case
@@ -49,45 +81,149 @@ func NewAuthUseCase(
 	authRepo repository.AuthenticationRepository,
 	tokenManager security.TokenManager,
 	passwordHash security.PasswordHash,
+	passwordPolicy PasswordPolicy,
+	loginLimiter LoginLimiter,
+	mfaUseCase MFAUseCase,
+	revocations security.RevocationStore,
+	audit AuditRecorder,
+	config AuthConfig,
 ) AuthUseCase {
 	return &authUseCase{
-		userRepo:     userRepo,
-		authRepo:     authRepo,
-		tokenManager: tokenManager,
-		passwordHash: passwordHash,
+		userRepo:       userRepo,
+		authRepo:       authRepo,
+		tokenManager:   tokenManager,
+		passwordHash:   passwordHash,
+		passwordPolicy: passwordPolicy,
+		loginLimiter:   loginLimiter,
+		mfaUseCase:     mfaUseCase,
+		revocations:    revocations,
+		audit:          audit,
+		config:         config,
 	}
 }
 
-func (u *authUseCase) Login(ctx context.Context, username, password string) (*LoginResponse, error) {
-	// Get user by username
-	user, err := u.userRepo.GetByUsername(ctx, username)
+func (u *authUseCase) Login(ctx context.Context, username, password string, client domain.ClientInfo) (*LoginResponse, error) {
+	user, response, err := u.login(ctx, username, password, client)
+
+	event := auditEvent(domain.AuditActionLogin, user, client, err)
+	if user == nil {
+		// Unknown usernames are recorded too, e.g. to spot password spraying
+		event.ActorUsername = username
+	}
+	if response != nil && response.MFARequired {
+		event.Metadata["mfa_required"] = true
+	}
+	u.audit.Record(ctx, event)
+
+	return response, err
+}
+
+// login returns the user as soon as it is known, also when the login fails
+func (u *authUseCase) login(ctx context.Context, username, password string, client domain.ClientInfo) (*domain.User, *LoginResponse, error) {
+	// Refuse locked usernames and addresses before spending a password compare
+	err := u.loginLimiter.Check(ctx, username, client.IPAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// No account has a longer username, the attempt only counts against the address
+	if utf8.RuneCountInString(username) > domain.MaxUsernameLength {
+		return nil, nil, u.loginFailed(ctx, username, client)
+	}
+
+	// Get user by username, inactive users are rejected below with a specific error
+	user, err := u.userRepo.GetByUsernameIncludingDeactivated(ctx, username)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
-			return nil, domain.ErrInvalidCredentials
+			return nil, nil, u.loginFailed(ctx, username, client)
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Verify password
 	err = u.passwordHash.Compare(user.Password, password)
 	if err != nil {
-		return nil, domain.ErrInvalidCredentials
+		return user, nil, u.loginFailed(ctx, username, client)
+	}
+
+	// Only reveal the account status to callers knowing the password
+	err = user.CheckActive()
+	if err != nil {
+		return user, nil, err
+	}
+
+	// Upgrade hashes of an outdated algorithm or cost while the password is at hand
+	if u.passwordHash.NeedsRehash(user.Password) {
+		u.rehashPassword(ctx, user, password)
 	}
 
+	// Users with a second factor, or whose role requires one, get a challenge instead of tokens
+	mfaEnabled, err := u.mfaUseCase.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return user, nil, err
+	}
+
+	if mfaEnabled || u.mfaUseCase.IsRequired(user.Role) {
+		mfaToken, err := u.tokenManager.GenerateMFAToken(user.ID)
+		if err != nil {
+			return user, nil, err
+		}
+
+		return user, &LoginResponse{
+			MFARequired:           true,
+			MFAEnrollmentRequired: !mfaEnabled,
+			MFAToken:              mfaToken,
+		}, nil
+	}
+
+	// Failures are only cleared once every factor is verified, so password logins
+	// cannot reset the count of wrong second factors
+	err = u.loginLimiter.RecordSuccess(ctx, username)
+	if err != nil {
+		return user, nil, err
+	}
+
+	response, err := u.issueSession(ctx, user, client)
+	return user, response, err
+}
+
+// issueSession starts a new session and returns its access and refresh tokens
+func (u *authUseCase) issueSession(ctx context.Context, user *domain.User, client domain.ClientInfo) (*LoginResponse, error) {
+	return startSession(ctx, u.tokenManager, u.authRepo, user, client)
+}
+
+// startSession is shared by every way of logging in
+func startSession(
+	ctx context.Context,
+	tokenManager security.TokenManager,
+	authRepo repository.AuthenticationRepository,
+	user *domain.User,
+	client domain.ClientInfo,
+) (*LoginResponse, error) {
+	// Every login starts a new session, identified by its token family
+	sessionID := "family-" + uuid.New().String()
+
 	// Generate access token
-	accessToken, err := u.tokenManager.GenerateAccessToken(user.ID, user.Role)
+	accessToken, err := tokenManager.GenerateAccessToken(user.ID, user.Role, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate refresh token
-	refreshToken, err := u.tokenManager.GenerateRefreshToken(user.ID)
+	refreshToken, err := tokenManager.GenerateRefreshToken(user.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store refresh token
-	err = u.authRepo.AddToken(ctx, refreshToken, user.ID)
+	// Store refresh token as the first token of a new family
+	err = authRepo.AddToken(ctx, &domain.Authentication{
+		TokenHash: security.HashToken(refreshToken),
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		ExpiresAt: time.Now().Add(tokenManager.RefreshTokenAge()),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -98,60 +234,185 @@ func (u *authUseCase) Login(ctx context.Context, username, password string) (*Lo
 	}, nil
 }
 
-func (u *authUseCase) RefreshToken(ctx context.Context, refreshToken string) (*RefreshTokenResponse, error) {
+// rehashPassword stores a fresh hash of password.
+// Failing to do so must not fail the login, the old hash stays valid.
+func (u *authUseCase) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	hashedPassword, err := u.passwordHash.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password of user '%s': %v\n", user.ID, err)
+		return
+	}
+
+	user.Password = hashedPassword
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		log.Printf("Failed to store rehashed password of user '%s': %v\n", user.ID, err)
+	}
+}
+
+// loginFailed records a failed login attempt and returns the error for the caller
+func (u *authUseCase) loginFailed(ctx context.Context, username string, client domain.ClientInfo) error {
+	if err := u.loginLimiter.RecordFailure(ctx, username, client.IPAddress); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
+func (u *authUseCase) RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*RefreshTokenResponse, error) {
+	stored, response, err := u.refreshToken(ctx, refreshToken, client)
+	u.audit.Record(ctx, sessionAuditEvent(domain.AuditActionRefresh, stored, client, err))
+	return response, err
+}
+
+// refreshToken returns the stored token as soon as it is known, also when the refresh fails
+func (u *authUseCase) refreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.Authentication, *RefreshTokenResponse, error) {
 	// Verify refresh token
 	payload, err := u.tokenManager.VerifyRefreshToken(refreshToken)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Check if refresh token exists in database
-	err = u.authRepo.CheckTokenAvailability(ctx, refreshToken)
+	stored, err := u.authRepo.GetToken(ctx, security.HashToken(refreshToken))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// A retired token being presented again means it has leaked,
+	// so the whole family is revoked and the user must login again
+	if stored.IsRevoked() {
+		return stored, nil, u.revokeFamily(ctx, stored.FamilyID)
 	}
 
 	// Get user to get the role
-	user, err := u.userRepo.GetByID(ctx, payload.UserID)
+	user, err := u.userRepo.GetByIDIncludingDeactivated(ctx, payload.UserID)
 	if err != nil {
-		return nil, err
+		return stored, nil, err
+	}
+
+	err = user.CheckActive()
+	if err != nil {
+		return stored, nil, err
 	}
 
 	// Generate new access token
-	accessToken, err := u.tokenManager.GenerateAccessToken(user.ID, user.Role)
+	accessToken, err := u.tokenManager.GenerateAccessToken(user.ID, user.Role, stored.FamilyID)
 	if err != nil {
-		return nil, err
+		return stored, nil, err
+	}
+
+	// Generate new refresh token
+	newRefreshToken, err := u.tokenManager.GenerateRefreshToken(user.ID)
+	if err != nil {
+		return stored, nil, err
+	}
+
+	// Retire the presented token and store its replacement
+	err = u.authRepo.RotateToken(ctx, stored.TokenHash, &domain.Authentication{
+		TokenHash: security.HashToken(newRefreshToken),
+		UserID:    user.ID,
+		FamilyID:  stored.FamilyID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		ExpiresAt: time.Now().Add(u.tokenManager.RefreshTokenAge()),
+	})
+	if err != nil {
+		if err == domain.ErrRefreshTokenReused {
+			return stored, nil, u.revokeFamily(ctx, stored.FamilyID)
+		}
+		return stored, nil, err
 	}
 
-	return &RefreshTokenResponse{
-		AccessToken: accessToken,
+	return stored, &RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
 	}, nil
 }
 
-func (u *authUseCase) Logout(ctx context.Context, refreshToken string) error {
+// revokeFamily deletes every token of a family after reuse was detected
+func (u *authUseCase) revokeFamily(ctx context.Context, familyID string) error {
+	if err := u.authRepo.DeleteTokenFamily(ctx, familyID); err != nil {
+		return err
+	}
+	u.revocations.RevokeSession(familyID)
+	return domain.ErrRefreshTokenReused
+}
+
+func (u *authUseCase) Logout(ctx context.Context, refreshToken string, client domain.ClientInfo) error {
+	stored, err := u.logout(ctx, refreshToken)
+	u.audit.Record(ctx, sessionAuditEvent(domain.AuditActionLogout, stored, client, err))
+	return err
+}
+
+// logout returns the stored token as soon as it is known, also when the logout fails
+func (u *authUseCase) logout(ctx context.Context, refreshToken string) (*domain.Authentication, error) {
 	// Verify refresh token
 	_, err := u.tokenManager.VerifyRefreshToken(refreshToken)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Delete refresh token from database
-	err = u.authRepo.DeleteToken(ctx, refreshToken)
+	// Only the current token of a family can be used to logout
+	stored, err := u.authRepo.GetToken(ctx, security.HashToken(refreshToken))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if stored.IsRevoked() {
+		return stored, u.revokeFamily(ctx, stored.FamilyID)
+	}
+
+	// Delete the whole family, including tokens retired by rotation
+	err = u.authRepo.DeleteTokenFamily(ctx, stored.FamilyID)
+	if err != nil {
+		return stored, err
+	}
+
+	// Access tokens of the session stop working right away
+	u.revocations.RevokeSession(stored.FamilyID)
+
+	return stored, nil
+}
+
+// sessionAuditEvent creates an event about the session of stored, which is nil when the token is unknown
+func sessionAuditEvent(action domain.AuditAction, stored *domain.Authentication, client domain.ClientInfo, err error) *domain.AuditEvent {
+	event := auditEvent(action, nil, client, err)
+	if stored != nil {
+		event.ActorID = stored.UserID
+		event.Target = stored.FamilyID
+	}
+	return event
 }
 
-func (u *authUseCase) Register(ctx context.Context, req *RegisterRequest) error {
+func (u *authUseCase) Register(ctx context.Context, req *RegisterRequest, client domain.ClientInfo) error {
+	userID, err := u.register(ctx, req)
+
+	event := auditEvent(domain.AuditActionRegister, nil, client, err)
+	event.ActorID = userID
+	event.ActorUsername = req.Username
+	u.audit.Record(ctx, event)
+
+	return err
+}
+
+// register returns the ID of the created user
+func (u *authUseCase) register(ctx context.Context, req *RegisterRequest) (string, error) {
+	// Without self-registration users only join through invitations
+	if !u.config.AllowSelfRegistration {
+		return "", domain.ErrRegistrationClosed
+	}
+
+	err := u.passwordPolicy.Check(ctx, "password", &domain.User{Username: req.Username}, req.Password)
+	if err != nil {
+		return "", err
+	}
+
 	// Generate user ID
 	userID := "user-" + uuid.New().String()
 
 	// Hash password
 	hashedPassword, err := u.passwordHash.Hash(req.Password)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Create user
@@ -160,13 +421,183 @@ func (u *authUseCase) Register(ctx context.Context, req *RegisterRequest) error
 		Username: req.Username,
 		Password: hashedPassword,
 		Fullname: req.Fullname,
-		Role:     req.Role,
+		Role:     domain.RoleStaff,
 	}
 
 	err = u.userRepo.Create(ctx, user)
+	if err != nil {
+		return "", err
+	}
+
+	u.passwordPolicy.Remember(ctx, userID, hashedPassword)
+
+	return userID, nil
+}
+
+func (u *authUseCase) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*domain.Session, error) {
+	sessions, err := u.authRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flag the session the request was made from
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+func (u *authUseCase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	err := u.authRepo.DeleteSession(ctx, userID, sessionID)
 	if err != nil {
 		return err
 	}
 
+	u.revocations.RevokeSession(sessionID)
+
 	return nil
 }
+
+func (u *authUseCase) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) (int64, error) {
+	// Without a current session every session would be revoked
+	if currentSessionID == "" {
+		return 0, domain.ErrSessionNotFound
+	}
+
+	sessions, err := u.authRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := u.authRepo.DeleteOtherSessions(ctx, userID, currentSessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, session := range sessions {
+		if session.ID != currentSessionID {
+			u.revocations.RevokeSession(session.ID)
+		}
+	}
+
+	return count, nil
+}
+
+func (u *authUseCase) UnlockAccount(ctx context.Context, username string) error {
+	return u.loginLimiter.Unlock(ctx, username)
+}
+
+// VerifyMFALogin completes a login with the second factor of an enrolled user
+func (u *authUseCase) VerifyMFALogin(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*LoginResponse, error) {
+	user, response, err := u.verifyMFALogin(ctx, mfaToken, code, client)
+	u.audit.Record(ctx, auditEvent(domain.AuditActionMFALogin, user, client, err))
+	return response, err
+}
+
+func (u *authUseCase) verifyMFALogin(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*domain.User, *LoginResponse, error) {
+	user, err := u.mfaChallengeUser(ctx, mfaToken, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = u.mfaUseCase.Verify(ctx, user.ID, code)
+	if err != nil {
+		return user, nil, u.mfaFailed(ctx, user, client, err)
+	}
+
+	err = u.loginLimiter.RecordSuccess(ctx, user.Username)
+	if err != nil {
+		return user, nil, err
+	}
+
+	response, err := u.issueSession(ctx, user, client)
+	return user, response, err
+}
+
+// BeginMFAEnrollment starts enrollment for a user whose role requires a second factor
+func (u *authUseCase) BeginMFAEnrollment(ctx context.Context, mfaToken string) (*domain.MFAEnrollment, error) {
+	payload, err := u.tokenManager.VerifyMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.mfaUseCase.Enroll(ctx, payload.UserID)
+}
+
+// CompleteMFAEnrollment confirms the enrollment started during login and completes the login
+func (u *authUseCase) CompleteMFAEnrollment(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*MFAEnrollmentResponse, error) {
+	user, response, err := u.completeMFAEnrollment(ctx, mfaToken, code, client)
+
+	event := auditEvent(domain.AuditActionMFALogin, user, client, err)
+	event.Metadata["mfa_enrollment"] = true
+	u.audit.Record(ctx, event)
+
+	return response, err
+}
+
+func (u *authUseCase) completeMFAEnrollment(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*domain.User, *MFAEnrollmentResponse, error) {
+	user, err := u.mfaChallengeUser(ctx, mfaToken, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recoveryCodes, err := u.mfaUseCase.Activate(ctx, user.ID, code)
+	if err != nil {
+		return user, nil, u.mfaFailed(ctx, user, client, err)
+	}
+
+	err = u.loginLimiter.RecordSuccess(ctx, user.Username)
+	if err != nil {
+		return user, nil, err
+	}
+
+	response, err := u.issueSession(ctx, user, client)
+	if err != nil {
+		return user, nil, err
+	}
+
+	return user, &MFAEnrollmentResponse{
+		LoginResponse: response,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// mfaChallengeUser resolves the user of an MFA challenge token.
+// Second factor attempts share the lockout of password attempts.
+func (u *authUseCase) mfaChallengeUser(ctx context.Context, mfaToken string, client domain.ClientInfo) (*domain.User, error) {
+	payload, err := u.tokenManager.VerifyMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetByIDIncludingDeactivated(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = user.CheckActive()
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.loginLimiter.Check(ctx, user.Username, client.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// mfaFailed records a wrong second factor as a failed login attempt
+func (u *authUseCase) mfaFailed(ctx context.Context, user *domain.User, client domain.ClientInfo, err error) error {
+	if err != domain.ErrInvalidMFACode {
+		return err
+	}
+
+	if recordErr := u.loginLimiter.RecordFailure(ctx, user.Username, client.IPAddress); recordErr != nil {
+		return recordErr
+	}
+
+	return err
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users []*domain.User
+}
+
+func (r *fakeUserRepository) GetByUsernameIncludingDeactivated(ctx context.Context, username string) (*domain.User, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Username, username) {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeUserRepository) GetByIDIncludingDeactivated(ctx context.Context, id string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+// fakeLoginAttemptRepository keeps attempts in memory and never forgets failures
+type fakeLoginAttemptRepository struct {
+	attempts map[string]*domain.LoginAttempt
+}
+
+func (r *fakeLoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, domain.ErrLoginAttemptNotFound
+	}
+	return attempt, nil
+}
+
+func (r *fakeLoginAttemptRepository) RecordFailure(ctx context.Context, key string, window, lockoutReset time.Duration) (*domain.LoginAttempt, error) {
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = &domain.LoginAttempt{Key: key}
+		r.attempts[key] = attempt
+	}
+	attempt.FailedCount++
+	attempt.LastFailureAt = time.Now()
+	return attempt, nil
+}
+
+func (r *fakeLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	attempt := r.attempts[key]
+	attempt.LockedUntil = &until
+	attempt.LockoutCount++
+	attempt.FailedCount = 0
+	return nil
+}
+
+func (r *fakeLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	if _, ok := r.attempts[key]; !ok {
+		return domain.ErrLoginAttemptNotFound
+	}
+	delete(r.attempts, key)
+	return nil
+}
+
+// fakePasswordHash stores passwords as they are
+type fakePasswordHash struct{}
+
+func (fakePasswordHash) Hash(password string) (string, error) { return password, nil }
+
+func (fakePasswordHash) Compare(hashedPassword, password string) error {
+	if hashedPassword != password {
+		return errors.New("password mismatch")
+	}
+	return nil
+}
+
+func (fakePasswordHash) NeedsRehash(hashedPassword string) bool { return false }
+
+// fakeTokenManager issues MFA tokens naming the user in clear
+type fakeTokenManager struct {
+	security.TokenManager
+}
+
+func (fakeTokenManager) GenerateMFAToken(userID string) (string, error) {
+	return "mfa:" + userID, nil
+}
+
+func (fakeTokenManager) VerifyMFAToken(tokenString string) (*security.TokenPayload, error) {
+	userID, ok := strings.CutPrefix(tokenString, "mfa:")
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	return &security.TokenPayload{UserID: userID}, nil
+}
+
+// fakeMFAUseCase accepts the code "123456" of enrolled users
+type fakeMFAUseCase struct {
+	MFAUseCase
+}
+
+func (fakeMFAUseCase) IsEnabled(ctx context.Context, userID string) (bool, error) { return true, nil }
+
+func (fakeMFAUseCase) IsRequired(role domain.UserRole) bool { return false }
+
+func (fakeMFAUseCase) Verify(ctx context.Context, userID, code string) error {
+	if code != "123456" {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+type discardAuditRecorder struct{}
+
+func (discardAuditRecorder) Record(ctx context.Context, event *domain.AuditEvent) {}
+
+func testLoginUser() *domain.User {
+	return &domain.User{
+		ID:       "user-1",
+		Username: "alice",
+		Password: "correct horse",
+		Role:     domain.RoleStaff,
+		Status:   domain.UserStatusActive,
+	}
+}
+
+// newTestAuthUseCase returns an auth use case knowing users whose accounts
+// lock after three failures, and the attempts recorded by its login limiter
+func newTestAuthUseCase(users ...*domain.User) (AuthUseCase, *fakeLoginAttemptRepository) {
+	attemptRepo := &fakeLoginAttemptRepository{attempts: map[string]*domain.LoginAttempt{}}
+	loginLimiter := NewLoginLimiter(attemptRepo, LoginLimiterConfig{
+		MaxUserAttempts: 3,
+		MaxIPAttempts:   100,
+		FailureWindow:   time.Hour,
+		BaseLockout:     time.Minute,
+		MaxLockout:      time.Hour,
+		LockoutReset:    24 * time.Hour,
+	})
+
+	authUseCase := NewAuthUseCase(
+		&fakeUserRepository{users: users},
+		nil,
+		fakeTokenManager{},
+		fakePasswordHash{},
+		nil,
+		loginLimiter,
+		fakeMFAUseCase{},
+		nil,
+		discardAuditRecorder{},
+		AuthConfig{},
+	)
+	return authUseCase, attemptRepo
+}
+
+func TestLoginDoesNotResetSecondFactorFailures(t *testing.T) {
+	user := testLoginUser()
+	authUseCase, _ := newTestAuthUseCase(user)
+
+	ctx := context.Background()
+	client := domain.ClientInfo{IPAddress: "192.0.2.1"}
+
+	login := func() string {
+		t.Helper()
+		response, err := authUseCase.Login(ctx, user.Username, user.Password, client)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if !response.MFARequired {
+			t.Fatalf("Login() = %+v, want an MFA challenge", response)
+		}
+		return response.MFAToken
+	}
+
+	guess := func(mfaToken string) error {
+		t.Helper()
+		_, err := authUseCase.VerifyMFALogin(ctx, mfaToken, "000000", client)
+		return err
+	}
+
+	// Two wrong codes, then a password login that must not clear them
+	mfaToken := login()
+	for i := 0; i < 2; i++ {
+		if err := guess(mfaToken); err != domain.ErrInvalidMFACode {
+			t.Fatalf("VerifyMFALogin() error = %v, want %v", err, domain.ErrInvalidMFACode)
+		}
+	}
+
+	// The third wrong code locks the account
+	mfaToken = login()
+	if err := guess(mfaToken); err != domain.ErrInvalidMFACode {
+		t.Fatalf("VerifyMFALogin() error = %v, want %v", err, domain.ErrInvalidMFACode)
+	}
+
+	if err := guess(mfaToken); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("VerifyMFALogin() after three wrong codes error = %v, want %v", err, domain.ErrAccountLocked)
+	}
+	if _, err := authUseCase.Login(ctx, user.Username, user.Password, client); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("Login() after three wrong codes error = %v, want %v", err, domain.ErrAccountLocked)
+	}
+}
+
+func TestLoginWithOverlongUsername(t *testing.T) {
+	authUseCase, attemptRepo := newTestAuthUseCase(testLoginUser())
+	client := domain.ClientInfo{IPAddress: "192.0.2.1"}
+
+	username := strings.Repeat("a", domain.MaxUsernameLength+1)
+	_, err := authUseCase.Login(context.Background(), username, "password", client)
+	if err != domain.ErrInvalidCredentials {
+		t.Fatalf("Login() error = %v, want %v", err, domain.ErrInvalidCredentials)
+	}
+
+	if len(attemptRepo.attempts) != 1 || attemptRepo.attempts["ip:"+client.IPAddress] == nil {
+		t.Errorf("recorded attempts = %v, want only the IP address", attemptRepo.attempts)
+	}
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoryUseCase defines product category tree use case interface.
+// rootID limits List and Tree to the subtree of a category, the whole tree is returned when empty.
+type CategoryUseCase interface {
+	List(ctx context.Context, rootID string) ([]*domain.Category, error)
+	Tree(ctx context.Context, rootID string) ([]*domain.Category, error)
+	Get(ctx context.Context, id string) (*domain.Category, error)
+	Create(ctx context.Context, req *CreateCategoryRequest) (*domain.Category, error)
+	Update(ctx context.Context, id string, req *UpdateCategoryRequest) (*domain.Category, error)
+	Move(ctx context.Context, id string, req *MoveCategoryRequest) (*domain.Category, error)
+	// Delete removes a category without subcategories and products
+	Delete(ctx context.Context, id string) error
+}
+
+// CreateCategoryRequest represents category creation request, an empty ParentID creates a top-level category
+type CreateCategoryRequest struct {
+	ParentID    string `json:"parent_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// UpdateCategoryRequest represents category update request, omitted fields are left unchanged
+type UpdateCategoryRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// MoveCategoryRequest represents moving a category with its subtree, an empty ParentID moves it to the top level
+type MoveCategoryRequest struct {
+	ParentID string `json:"parent_id"`
+}
+
+type categoryUseCase struct {
+	categoryRepo repository.CategoryRepository
+}
+
+// NewCategoryUseCase creates a new category use case
+func NewCategoryUseCase(categoryRepo repository.CategoryRepository) CategoryUseCase {
+	return &categoryUseCase{
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (u *categoryUseCase) List(ctx context.Context, rootID string) ([]*domain.Category, error) {
+	return u.categoryRepo.List(ctx, rootID)
+}
+
+func (u *categoryUseCase) Tree(ctx context.Context, rootID string) ([]*domain.Category, error) {
+	categories, err := u.categoryRepo.List(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BuildCategoryTree(categories), nil
+}
+
+func (u *categoryUseCase) Get(ctx context.Context, id string) (*domain.Category, error) {
+	return u.categoryRepo.GetByID(ctx, id)
+}
+
+func (u *categoryUseCase) Create(ctx context.Context, req *CreateCategoryRequest) (*domain.Category, error) {
+	now := time.Now()
+	category := &domain.Category{
+		ID:          "category-" + uuid.New().String(),
+		ParentID:    strings.TrimSpace(req.ParentID),
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err := u.validate(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.categoryRepo.Create(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	// Path and depth are derived from the tree
+	return u.categoryRepo.GetByID(ctx, category.ID)
+}
+
+func (u *categoryUseCase) Update(ctx context.Context, id string, req *UpdateCategoryRequest) (*domain.Category, error) {
+	category, err := u.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		category.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		category.Description = strings.TrimSpace(*req.Description)
+	}
+
+	err = u.validate(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	category.UpdatedAt = time.Now()
+	err = u.categoryRepo.Update(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.categoryRepo.GetByID(ctx, id)
+}
+
+// Move places the category and its subtree below another category or at the top level
+func (u *categoryUseCase) Move(ctx context.Context, id string, req *MoveCategoryRequest) (*domain.Category, error) {
+	parentID := strings.TrimSpace(req.ParentID)
+	if parentID == id {
+		return nil, domain.ErrCategoryCycle
+	}
+
+	_, err := u.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.checkParent(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.categoryRepo.Move(ctx, id, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.categoryRepo.GetByID(ctx, id)
+}
+
+func (u *categoryUseCase) Delete(ctx context.Context, id string) error {
+	return u.categoryRepo.Delete(ctx, id)
+}
+
+// validate returns a *domain.ValidationError listing every invalid field of category
+func (u *categoryUseCase) validate(ctx context.Context, category *domain.Category) error {
+	var violations []domain.FieldError
+	if category.Name == "" {
+		violations = append(violations, domain.FieldError{Field: "name", Code: "required", Message: "Field is required"})
+	}
+	checkLength(&violations, "name", category.Name, 100)
+
+	if len(violations) > 0 {
+		return &domain.ValidationError{Errors: violations}
+	}
+
+	return u.checkParent(ctx, category.ParentID)
+}
+
+// checkParent returns a *domain.ValidationError when parentID is set but does not exist
+func (u *categoryUseCase) checkParent(ctx context.Context, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+
+	_, err := u.categoryRepo.GetByID(ctx, parentID)
+	if err == domain.ErrCategoryNotFound {
+		return &domain.ValidationError{Errors: []domain.FieldError{
+			{Field: "parent_id", Code: "not_found", Message: "Parent category not found"},
+		}}
+	}
+
+	return err
+}
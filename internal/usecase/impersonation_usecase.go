@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImpersonationUseCase lets admins act as another user for support.
+// Impersonation tokens are plain access tokens with an act claim; they cannot
+// be refreshed and every request made with them is audited.
+type ImpersonationUseCase interface {
+	Start(ctx context.Context, actor *domain.User, userID, reason string, client domain.ClientInfo) (*ImpersonationResponse, error)
+	// End revokes the impersonation token of sessionID before it expires
+	End(ctx context.Context, actor, user *domain.User, sessionID string, client domain.ClientInfo) error
+}
+
+// ImpersonationConfig configures impersonation
+type ImpersonationConfig struct {
+	// TokenAge is the lifetime of impersonation tokens, capped at the access token lifetime
+	TokenAge time.Duration
+}
+
+// ImpersonationResponse represents an impersonation token
+type ImpersonationResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+}
+
+type impersonationUseCase struct {
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	tokenManager security.TokenManager
+	revocations  security.RevocationStore
+	audit        AuditRecorder
+	config       ImpersonationConfig
+}
+
+// NewImpersonationUseCase creates a new impersonation use case
+func NewImpersonationUseCase(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	tokenManager security.TokenManager,
+	revocations security.RevocationStore,
+	audit AuditRecorder,
+	config ImpersonationConfig,
+) ImpersonationUseCase {
+	// Revocations are only kept as long as access tokens live
+	if config.TokenAge <= 0 || config.TokenAge > tokenManager.AccessTokenAge() {
+		config.TokenAge = tokenManager.AccessTokenAge()
+	}
+
+	return &impersonationUseCase{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		tokenManager: tokenManager,
+		revocations:  revocations,
+		audit:        audit,
+		config:       config,
+	}
+}
+
+func (u *impersonationUseCase) Start(ctx context.Context, actor *domain.User, userID, reason string, client domain.ClientInfo) (*ImpersonationResponse, error) {
+	reason = strings.TrimSpace(reason)
+
+	user, sessionID, response, err := u.start(ctx, actor, userID)
+
+	event := auditEvent(domain.AuditActionImpersonationStart, actor, client, err)
+	event.Target = userID
+	// The reason key is taken by the error of a refused impersonation
+	event.Metadata["justification"] = reason
+	if user != nil {
+		event.Metadata["impersonated_username"] = user.Username
+	}
+	if response != nil {
+		event.Metadata["session_id"] = sessionID
+		event.Metadata["expires_in"] = response.ExpiresIn
+	}
+	u.audit.Record(ctx, event)
+
+	return response, err
+}
+
+// start returns the impersonated user as soon as it is known, also when impersonation is refused
+func (u *impersonationUseCase) start(ctx context.Context, actor *domain.User, userID string) (*domain.User, string, *ImpersonationResponse, error) {
+	if userID == actor.ID {
+		return nil, "", nil, domain.ErrCannotImpersonate
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = user.CheckActive()
+	if err != nil {
+		return user, "", nil, err
+	}
+
+	// Admins cannot hide their actions behind another admin
+	permissions, err := u.roleRepo.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return user, "", nil, err
+	}
+	if slices.Contains(permissions, domain.PermissionUserImpersonate) {
+		return user, "", nil, domain.ErrCannotImpersonate
+	}
+
+	// A session of its own lets the impersonation be ended early
+	sessionID := "impersonation-" + uuid.New().String()
+
+	accessToken, err := u.tokenManager.GenerateImpersonationToken(user.ID, user.Role, actor.ID, sessionID, u.config.TokenAge)
+	if err != nil {
+		return user, "", nil, err
+	}
+
+	return user, sessionID, &ImpersonationResponse{
+		AccessToken: accessToken,
+		ExpiresIn:   int(u.config.TokenAge / time.Second),
+		UserID:      user.ID,
+		Username:    user.Username,
+	}, nil
+}
+
+func (u *impersonationUseCase) End(ctx context.Context, actor, user *domain.User, sessionID string, client domain.ClientInfo) error {
+	if actor == nil {
+		return domain.ErrNotImpersonating
+	}
+
+	u.revocations.RevokeSession(sessionID)
+
+	event := auditEvent(domain.AuditActionImpersonationEnd, actor, client, nil)
+	event.Target = user.ID
+	event.Metadata["impersonated_username"] = user.Username
+	event.Metadata["session_id"] = sessionID
+	u.audit.Record(ctx, event)
+
+	return nil
+}
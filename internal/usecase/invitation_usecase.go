@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvitationUseCase defines invitation-based onboarding use case interface
+type InvitationUseCase interface {
+	Create(ctx context.Context, req *CreateInvitationRequest, createdBy string) (*InvitationResponse, error)
+	List(ctx context.Context, pendingOnly bool) ([]*domain.Invitation, error)
+	Revoke(ctx context.Context, id string) error
+	Accept(ctx context.Context, req *AcceptInvitationRequest) error
+}
+
+// InvitationConfig configures invitation tokens
+type InvitationConfig struct {
+	// URL is the page the invitation link points to, the token is appended as query parameter
+	URL         string
+	TokenAge    time.Duration
+	MaxTokenAge time.Duration
+}
+
+// CreateInvitationRequest represents invitation create request
+type CreateInvitationRequest struct {
+	Role domain.UserRole `json:"role"`
+	// ExpiresIn is the lifetime of the invitation in seconds, the configured default when zero
+	ExpiresIn int `json:"expires_in"`
+}
+
+// InvitationResponse represents a created invitation.
+// The token is only returned once, it cannot be recovered later.
+type InvitationResponse struct {
+	*domain.Invitation
+	Token string `json:"token"`
+	Link  string `json:"link"`
+}
+
+// AcceptInvitationRequest represents invitation redemption request
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Fullname string `json:"fullname"`
+}
+
+type invitationUseCase struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	passwordHash   security.PasswordHash
+	passwordPolicy PasswordPolicy
+	config         InvitationConfig
+}
+
+// NewInvitationUseCase creates a new invitation use case
+func NewInvitationUseCase(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	passwordHash security.PasswordHash,
+	passwordPolicy PasswordPolicy,
+	config InvitationConfig,
+) InvitationUseCase {
+	return &invitationUseCase{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		passwordHash:   passwordHash,
+		passwordPolicy: passwordPolicy,
+		config:         config,
+	}
+}
+
+func (u *invitationUseCase) Create(ctx context.Context, req *CreateInvitationRequest, createdBy string) (*InvitationResponse, error) {
+	age := u.config.TokenAge
+	if req.ExpiresIn > 0 {
+		age = time.Duration(req.ExpiresIn) * time.Second
+	}
+	if age > u.config.MaxTokenAge {
+		return nil, domain.ErrBadRequest
+	}
+
+	token, err := security.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &domain.Invitation{
+		ID:        "invitation-" + uuid.New().String(),
+		Role:      req.Role,
+		TokenHash: security.HashToken(token),
+		CreatedBy: createdBy,
+		ExpiresAt: now.Add(age),
+		CreatedAt: now,
+	}
+
+	err = u.invitationRepo.Create(ctx, invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvitationResponse{
+		Invitation: invitation,
+		Token:      token,
+		Link:       linkWithToken(u.config.URL, token),
+	}, nil
+}
+
+func (u *invitationUseCase) List(ctx context.Context, pendingOnly bool) ([]*domain.Invitation, error) {
+	return u.invitationRepo.List(ctx, pendingOnly)
+}
+
+func (u *invitationUseCase) Revoke(ctx context.Context, id string) error {
+	return u.invitationRepo.Delete(ctx, id)
+}
+
+func (u *invitationUseCase) Accept(ctx context.Context, req *AcceptInvitationRequest) error {
+	// Checked before consuming so a rejected password keeps the invitation untouched
+	err := u.passwordPolicy.Check(ctx, "password", &domain.User{Username: req.Username}, req.Password)
+	if err != nil {
+		return err
+	}
+
+	// Generate user ID
+	userID := "user-" + uuid.New().String()
+
+	// Consume first so two concurrent redemptions cannot both succeed
+	invitation, err := u.invitationRepo.Consume(ctx, security.HashToken(req.Token), userID)
+	if err != nil {
+		return err
+	}
+
+	err = u.createUser(ctx, userID, invitation.Role, req)
+	if err != nil {
+		// Let the invitee retry, e.g. with another username
+		if releaseErr := u.invitationRepo.Release(ctx, invitation.ID); releaseErr != nil {
+			return releaseErr
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *invitationUseCase) createUser(ctx context.Context, userID string, role domain.UserRole, req *AcceptInvitationRequest) error {
+	// Hash password
+	hashedPassword, err := u.passwordHash.Hash(req.Password)
+	if err != nil {
+		return err
+	}
+
+	user := &domain.User{
+		ID:       userID,
+		Username: req.Username,
+		Password: hashedPassword,
+		Fullname: req.Fullname,
+		Role:     role,
+	}
+
+	err = u.userRepo.Create(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	u.passwordPolicy.Remember(ctx, userID, hashedPassword)
+
+	return nil
+}
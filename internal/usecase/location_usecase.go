@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultLocationPageSize = 50
+	maxLocationPageSize     = 200
+)
+
+// LocationUseCase defines warehouse location use case interface.
+// Zones sit at the top of a warehouse, aisles inside zones and bins inside aisles.
+type LocationUseCase interface {
+	// List returns the locations of the warehouses user may operate
+	List(ctx context.Context, user *domain.User, req *ListLocationsRequest) (*LocationListResponse, error)
+	Get(ctx context.Context, id string) (*domain.Location, error)
+	Create(ctx context.Context, req *CreateLocationRequest) (*domain.Location, error)
+	Update(ctx context.Context, id string, req *UpdateLocationRequest) (*domain.Location, error)
+	// Delete removes a location without child locations
+	Delete(ctx context.Context, id string) error
+}
+
+// ListLocationsRequest represents location list query
+type ListLocationsRequest struct {
+	Search      string
+	WarehouseID string
+	ParentID    string
+	Level       domain.LocationLevel
+	Type        domain.LocationType
+	Active      *bool
+	Page        int
+	PageSize    int
+}
+
+// LocationListResponse represents one page of locations
+type LocationListResponse struct {
+	Locations  []*domain.Location `json:"locations"`
+	Pagination Pagination         `json:"pagination"`
+}
+
+// CreateLocationRequest represents location creation request.
+// Type defaults to storage and locations are active unless Active is false.
+type CreateLocationRequest struct {
+	WarehouseID string               `json:"warehouse_id"`
+	ParentID    string               `json:"parent_id"`
+	Code        string               `json:"code"`
+	Name        string               `json:"name"`
+	Level       domain.LocationLevel `json:"level"`
+	Type        domain.LocationType  `json:"type"`
+	Capacity    *domain.Decimal      `json:"capacity"`
+	Active      *bool                `json:"active"`
+}
+
+// UpdateLocationRequest represents location update request, omitted fields are left unchanged.
+// The warehouse, parent and level of a location are fixed at creation.
+type UpdateLocationRequest struct {
+	Code     *string              `json:"code"`
+	Name     *string              `json:"name"`
+	Type     *domain.LocationType `json:"type"`
+	Capacity *domain.Decimal      `json:"capacity"`
+	// RemoveCapacity makes the capacity unlimited
+	RemoveCapacity bool  `json:"remove_capacity"`
+	Active         *bool `json:"active"`
+}
+
+type locationUseCase struct {
+	locationRepo  repository.LocationRepository
+	warehouseRepo repository.WarehouseRepository
+}
+
+// NewLocationUseCase creates a new location use case
+func NewLocationUseCase(locationRepo repository.LocationRepository, warehouseRepo repository.WarehouseRepository) LocationUseCase {
+	return &locationUseCase{
+		locationRepo:  locationRepo,
+		warehouseRepo: warehouseRepo,
+	}
+}
+
+func (u *locationUseCase) List(ctx context.Context, user *domain.User, req *ListLocationsRequest) (*LocationListResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = defaultLocationPageSize
+	}
+	if pageSize > maxLocationPageSize {
+		pageSize = maxLocationPageSize
+	}
+
+	filter := repository.LocationFilter{
+		Search:      strings.TrimSpace(req.Search),
+		WarehouseID: strings.TrimSpace(req.WarehouseID),
+		ParentID:    strings.TrimSpace(req.ParentID),
+		Level:       req.Level,
+		Type:        req.Type,
+		Active:      req.Active,
+		Limit:       pageSize,
+		Offset:      (page - 1) * pageSize,
+	}
+	if !user.HasPermission(domain.PermissionWarehouseManage) {
+		filter.UserID = user.ID
+	}
+
+	locations, total, err := u.locationRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocationListResponse{
+		Locations: locations,
+		Pagination: Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (u *locationUseCase) Get(ctx context.Context, id string) (*domain.Location, error) {
+	return u.locationRepo.GetByID(ctx, id)
+}
+
+func (u *locationUseCase) Create(ctx context.Context, req *CreateLocationRequest) (*domain.Location, error) {
+	now := time.Now()
+	location := &domain.Location{
+		ID:          "location-" + uuid.New().String(),
+		WarehouseID: strings.TrimSpace(req.WarehouseID),
+		ParentID:    strings.TrimSpace(req.ParentID),
+		Code:        normalizeCode(req.Code),
+		Name:        strings.TrimSpace(req.Name),
+		Level:       req.Level,
+		Type:        req.Type,
+		Capacity:    req.Capacity,
+		Active:      req.Active == nil || *req.Active,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if location.Type == "" {
+		location.Type = domain.LocationStorage
+	}
+
+	err := validateLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.checkPlacement(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.locationRepo.Create(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (u *locationUseCase) Update(ctx context.Context, id string, req *UpdateLocationRequest) (*domain.Location, error) {
+	location, err := u.locationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code != nil {
+		location.Code = normalizeCode(*req.Code)
+	}
+	if req.Name != nil {
+		location.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Type != nil {
+		location.Type = *req.Type
+	}
+	if req.Capacity != nil {
+		location.Capacity = req.Capacity
+	}
+	if req.RemoveCapacity {
+		location.Capacity = nil
+	}
+	if req.Active != nil {
+		location.Active = *req.Active
+	}
+
+	err = validateLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	location.UpdatedAt = time.Now()
+	err = u.locationRepo.Update(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (u *locationUseCase) Delete(ctx context.Context, id string) error {
+	return u.locationRepo.Delete(ctx, id)
+}
+
+// checkPlacement returns a *domain.ValidationError unless the warehouse exists and the
+// parent is a location of the level above in the same warehouse
+func (u *locationUseCase) checkPlacement(ctx context.Context, location *domain.Location) error {
+	_, err := u.warehouseRepo.GetByID(ctx, location.WarehouseID)
+	if err == domain.ErrWarehouseNotFound {
+		return &domain.ValidationError{Errors: []domain.FieldError{
+			{Field: "warehouse_id", Code: "not_found", Message: "Warehouse not found"},
+		}}
+	}
+	if err != nil {
+		return err
+	}
+
+	parentLevel := location.Level.ParentLevel()
+	if parentLevel == "" {
+		if location.ParentID != "" {
+			return &domain.ValidationError{Errors: []domain.FieldError{
+				{Field: "parent_id", Code: "invalid", Message: "Zones cannot have a parent location"},
+			}}
+		}
+		return nil
+	}
+
+	if location.ParentID == "" {
+		return &domain.ValidationError{Errors: []domain.FieldError{
+			{Field: "parent_id", Code: "required", Message: "Location of level " + string(parentLevel) + " is required"},
+		}}
+	}
+
+	parent, err := u.locationRepo.GetByID(ctx, location.ParentID)
+	if err == domain.ErrLocationNotFound || (err == nil && parent.WarehouseID != location.WarehouseID) {
+		return &domain.ValidationError{Errors: []domain.FieldError{
+			{Field: "parent_id", Code: "not_found", Message: "Parent location not found in the warehouse"},
+		}}
+	}
+	if err != nil {
+		return err
+	}
+
+	if parent.Level != parentLevel {
+		return &domain.ValidationError{Errors: []domain.FieldError{
+			{Field: "parent_id", Code: "invalid", Message: "Parent location must be of level " + string(parentLevel)},
+		}}
+	}
+
+	return nil
+}
+
+// validateLocation returns a *domain.ValidationError listing every invalid field of location
+func validateLocation(location *domain.Location) error {
+	var violations []domain.FieldError
+	if location.WarehouseID == "" {
+		violations = append(violations, domain.FieldError{Field: "warehouse_id", Code: "required", Message: "Field is required"})
+	}
+	if location.Code == "" {
+		violations = append(violations, domain.FieldError{Field: "code", Code: "required", Message: "Field is required"})
+	}
+	checkLength(&violations, "code", location.Code, 50)
+	checkLength(&violations, "name", location.Name, 100)
+
+	if !location.Level.IsValid() {
+		violations = append(violations, domain.FieldError{Field: "level", Code: "invalid", Message: "Level must be zone, aisle or bin"})
+	}
+	if !location.Type.IsValid() {
+		violations = append(violations, domain.FieldError{
+			Field:   "type",
+			Code:    "invalid",
+			Message: "Type must be receiving, storage, picking or quarantine",
+		})
+	}
+
+	if location.Capacity != nil && location.Capacity.Sign() < 0 {
+		violations = append(violations, domain.FieldError{Field: "capacity", Code: "invalid", Message: "Capacity must not be negative"})
+	}
+
+	if len(violations) > 0 {
+		return &domain.ValidationError{Errors: violations}
+	}
+
+	return nil
+}
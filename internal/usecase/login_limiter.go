@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// LoginLimiter protects login against brute-force attempts
+type LoginLimiter interface {
+	Check(ctx context.Context, username, ipAddress string) error
+	RecordFailure(ctx context.Context, username, ipAddress string) error
+	RecordSuccess(ctx context.Context, username string) error
+	Unlock(ctx context.Context, username string) error
+}
+
+// LoginLimiterConfig configures login throttling
+type LoginLimiterConfig struct {
+	MaxUserAttempts int           // Failed attempts per username before the account is locked
+	MaxIPAttempts   int           // Failed attempts per IP address before the address is locked
+	FailureWindow   time.Duration // Failures older than this are forgotten
+	BaseLockout     time.Duration // First lockout duration, doubled on every following lockout
+	MaxLockout      time.Duration // Upper bound of the lockout duration
+	LockoutReset    time.Duration // Escalation is forgotten after this long without failures
+}
+
+type loginLimiter struct {
+	attemptRepo repository.LoginAttemptRepository
+	config      LoginLimiterConfig
+}
+
+// NewLoginLimiter creates a new login limiter
+func NewLoginLimiter(attemptRepo repository.LoginAttemptRepository, config LoginLimiterConfig) LoginLimiter {
+	return &loginLimiter{
+		attemptRepo: attemptRepo,
+		config:      config,
+	}
+}
+
+// Check returns a *domain.LockoutError when the username or the IP address is locked
+func (l *loginLimiter) Check(ctx context.Context, username, ipAddress string) error {
+	now := time.Now()
+
+	if err := l.checkKey(ctx, userAttemptKey(username), domain.ErrAccountLocked, now); err != nil {
+		return err
+	}
+
+	return l.checkKey(ctx, ipAttemptKey(ipAddress), domain.ErrTooManyLoginAttempts, now)
+}
+
+// RecordFailure counts a failed attempt against the IP address first, so it is
+// limited even when the username cannot be recorded. Usernames longer than any
+// account's are only counted against the address.
+func (l *loginLimiter) RecordFailure(ctx context.Context, username, ipAddress string) error {
+	if err := l.recordKeyFailure(ctx, ipAttemptKey(ipAddress), l.config.MaxIPAttempts); err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(username) > domain.MaxUsernameLength {
+		return nil
+	}
+
+	return l.recordKeyFailure(ctx, userAttemptKey(username), l.config.MaxUserAttempts)
+}
+
+// RecordSuccess clears the failures of the username once every factor of a login is verified.
+// Failures of the IP address are kept so one valid account cannot reset them.
+func (l *loginLimiter) RecordSuccess(ctx context.Context, username string) error {
+	err := l.attemptRepo.Delete(ctx, userAttemptKey(username))
+	if err != nil && err != domain.ErrLoginAttemptNotFound {
+		return err
+	}
+	return nil
+}
+
+func (l *loginLimiter) Unlock(ctx context.Context, username string) error {
+	return l.attemptRepo.Delete(ctx, userAttemptKey(username))
+}
+
+func (l *loginLimiter) checkKey(ctx context.Context, key string, lockErr error, now time.Time) error {
+	attempt, err := l.attemptRepo.Get(ctx, key)
+	if err != nil {
+		if err == domain.ErrLoginAttemptNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if attempt.IsLocked(now) {
+		return &domain.LockoutError{
+			Err:        lockErr,
+			RetryAfter: attempt.LockedUntil.Sub(now),
+		}
+	}
+
+	return nil
+}
+
+func (l *loginLimiter) recordKeyFailure(ctx context.Context, key string, maxAttempts int) error {
+	attempt, err := l.attemptRepo.RecordFailure(ctx, key, l.config.FailureWindow, l.config.LockoutReset)
+	if err != nil {
+		return err
+	}
+
+	if attempt.FailedCount < maxAttempts {
+		return nil
+	}
+
+	return l.attemptRepo.Lock(ctx, key, time.Now().Add(l.lockoutDuration(attempt.LockoutCount)))
+}
+
+// lockoutDuration doubles the base lockout for every previous lockout
+func (l *loginLimiter) lockoutDuration(previousLockouts int) time.Duration {
+	duration := l.config.BaseLockout
+	for i := 0; i < previousLockouts && duration < l.config.MaxLockout; i++ {
+		duration *= 2
+	}
+
+	if duration > l.config.MaxLockout {
+		return l.config.MaxLockout
+	}
+
+	return duration
+}
+
+func userAttemptKey(username string) string {
+	return "user:" + strings.ToLower(username)
+}
+
+func ipAttemptKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"time"
+)
+
+// recoveryCodeCount is the number of recovery codes issued at once
+const recoveryCodeCount = 10
+
+// MFAUseCase defines two-factor authentication use case interface
+type MFAUseCase interface {
+	Status(ctx context.Context, userID string) (*MFAStatus, error)
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+	IsRequired(role domain.UserRole) bool
+	Enroll(ctx context.Context, userID string) (*domain.MFAEnrollment, error)
+	Activate(ctx context.Context, userID, code string) ([]string, error)
+	Verify(ctx context.Context, userID, code string) error
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+}
+
+// MFAStatus represents the two-factor authentication state of a user
+type MFAStatus struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// MFAConfig configures two-factor authentication
+type MFAConfig struct {
+	Issuer        string            // Shown by authenticator apps next to the account
+	RequiredRoles []domain.UserRole // Roles that cannot login without a second factor
+}
+
+type mfaUseCase struct {
+	userRepo repository.UserRepository
+	mfaRepo  repository.MFARepository
+	config   MFAConfig
+}
+
+// NewMFAUseCase creates a new two-factor authentication use case
+func NewMFAUseCase(
+	userRepo repository.UserRepository,
+	mfaRepo repository.MFARepository,
+	config MFAConfig,
+) MFAUseCase {
+	return &mfaUseCase{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		config:   config,
+	}
+}
+
+func (u *mfaUseCase) Status(ctx context.Context, userID string) (*MFAStatus, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MFAStatus{Required: u.IsRequired(user.Role)}
+
+	status.Enabled, err = u.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.Enabled {
+		status.RecoveryCodesRemaining, err = u.mfaRepo.CountRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+func (u *mfaUseCase) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	mfa, err := u.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		if err == domain.ErrMFANotEnrolled {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return mfa.IsEnabled(), nil
+}
+
+func (u *mfaUseCase) IsRequired(role domain.UserRole) bool {
+	for _, required := range u.config.RequiredRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Enroll generates a new secret awaiting confirmation with Activate
+func (u *mfaUseCase) Enroll(ctx context.Context, userID string) (*domain.MFAEnrollment, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.mfaRepo.SavePending(ctx, &domain.UserMFA{
+		UserID: user.ID,
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret: secret,
+		URI:    security.TOTPURI(u.config.Issuer, user.Username, secret),
+	}, nil
+}
+
+// Activate confirms a pending enrollment and returns the initial recovery codes
+func (u *mfaUseCase) Activate(ctx context.Context, userID, code string) ([]string, error) {
+	mfa, err := u.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa.IsEnabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := security.ValidateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	err = u.mfaRepo.Enable(ctx, userID, step)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.issueRecoveryCodes(ctx, userID)
+}
+
+// Verify accepts either a TOTP code or an unused recovery code
+func (u *mfaUseCase) Verify(ctx context.Context, userID, code string) error {
+	mfa, err := u.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !mfa.IsEnabled() {
+		return domain.ErrMFANotEnrolled
+	}
+
+	if step, ok := security.ValidateTOTP(mfa.Secret, code, time.Now()); ok {
+		return u.mfaRepo.UseStep(ctx, userID, step)
+	}
+
+	return u.mfaRepo.UseRecoveryCode(ctx, userID, security.HashToken(security.NormalizeRecoveryCode(code)))
+}
+
+func (u *mfaUseCase) Disable(ctx context.Context, userID, code string) error {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if u.IsRequired(user.Role) {
+		return domain.ErrMFARequiredByPolicy
+	}
+
+	err = u.Verify(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	return u.mfaRepo.Delete(ctx, userID)
+}
+
+func (u *mfaUseCase) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	err := u.Verify(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.issueRecoveryCodes(ctx, userID)
+}
+
+// issueRecoveryCodes replaces the recovery codes of the user, only their hashes are stored
+func (u *mfaUseCase) issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes, err := security.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = security.HashToken(security.NormalizeRecoveryCode(code))
+	}
+
+	err = u.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"crypto/subtle"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// OIDCUseCase defines single sign-on use case interface
+type OIDCUseCase interface {
+	Authorize(ctx context.Context) (*OIDCAuthorization, error)
+	// Callback completes the login started by Authorize. browserState is the state
+	// the browser kept from Authorize and must match state, which binds the login
+	// to the browser that started it.
+	Callback(ctx context.Context, code, state, browserState string, client domain.ClientInfo) (*LoginResponse, error)
+}
+
+// OIDCGroupRole grants a role to members of an identity provider group
+type OIDCGroupRole struct {
+	Group string
+	Role  domain.UserRole
+}
+
+// OIDCConfig configures single sign-on users
+type OIDCConfig struct {
+	// GroupRoles are checked in order, the first matching role becomes the primary role
+	GroupRoles []OIDCGroupRole
+	// DefaultRole is granted when no group matches
+	DefaultRole domain.UserRole
+	// SyncRoles replaces the roles of existing users with the mapped roles on every login
+	SyncRoles bool
+	// StateAge is how long the user may take to log in at the provider
+	StateAge time.Duration
+	// MFAMethods are the amr values and MFAContexts the acr values proving that
+	// the provider checked a second factor. Users whose role requires two-factor
+	// authentication are refused without one of them.
+	MFAMethods  []string
+	MFAContexts []string
+}
+
+// OIDCAuthorization is the provider page to send the user to.
+// The provider returns the state unchanged together with the authorization code.
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+type oidcUseCase struct {
+	provider     security.OIDCProvider
+	oidcRepo     repository.OIDCRepository
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	authRepo     repository.AuthenticationRepository
+	tokenManager security.TokenManager
+	principals   security.PrincipalCache
+	mfaUseCase   MFAUseCase
+	audit        AuditRecorder
+	config       OIDCConfig
+}
+
+// NewOIDCUseCase creates a new single sign-on use case
+func NewOIDCUseCase(
+	provider security.OIDCProvider,
+	oidcRepo repository.OIDCRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	authRepo repository.AuthenticationRepository,
+	tokenManager security.TokenManager,
+	principals security.PrincipalCache,
+	mfaUseCase MFAUseCase,
+	audit AuditRecorder,
+	config OIDCConfig,
+) OIDCUseCase {
+	return &oidcUseCase{
+		provider:     provider,
+		oidcRepo:     oidcRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		authRepo:     authRepo,
+		tokenManager: tokenManager,
+		principals:   principals,
+		mfaUseCase:   mfaUseCase,
+		audit:        audit,
+		config:       config,
+	}
+}
+
+func (u *oidcUseCase) Authorize(ctx context.Context) (*OIDCAuthorization, error) {
+	state, err := security.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := security.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	codeVerifier, codeChallenge, err := security.NewPKCEChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := u.provider.AuthCodeURL(ctx, state, nonce, codeChallenge)
+	if err != nil {
+		log.Printf("Failed to reach identity provider: %v\n", err)
+		return nil, domain.ErrOIDCLoginFailed
+	}
+
+	now := time.Now()
+	err = u.oidcRepo.SaveState(ctx, &domain.OIDCLoginState{
+		StateHash:    security.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(u.config.StateAge),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthorization{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+	}, nil
+}
+
+func (u *oidcUseCase) Callback(ctx context.Context, code, state, browserState string, client domain.ClientInfo) (*LoginResponse, error) {
+	claims, user, response, err := u.callback(ctx, code, state, browserState, client)
+
+	event := auditEvent(domain.AuditActionSSOLogin, user, client, err)
+	if claims != nil {
+		event.Metadata["issuer"] = claims.Issuer
+		event.Metadata["subject"] = claims.Subject
+		if err == domain.ErrOIDCMFAMissing {
+			event.Metadata["amr"] = strings.Join(claims.AMR, " ")
+			event.Metadata["acr"] = claims.ACR
+		}
+		if user == nil {
+			event.ActorUsername = claims.PreferredUsername
+		}
+	}
+	u.audit.Record(ctx, event)
+
+	return response, err
+}
+
+// callback returns the claims and the user as soon as they are known, also when the login fails
+func (u *oidcUseCase) callback(ctx context.Context, code, state, browserState string, client domain.ClientInfo) (*security.OIDCClaims, *domain.User, *LoginResponse, error) {
+	// A state from another browser is refused without consuming it, its owner may still finish the login
+	if subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return nil, nil, nil, domain.ErrOIDCStateInvalid
+	}
+
+	// Consume first so an intercepted callback cannot be replayed
+	loginState, err := u.oidcRepo.ConsumeState(ctx, security.HashToken(state))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	claims, err := u.provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("Identity provider login failed: %v\n", err)
+		return nil, nil, nil, domain.ErrOIDCLoginFailed
+	}
+
+	roles := u.mapRoles(claims.Groups)
+
+	user, err := u.findUser(ctx, claims, roles)
+	if err == domain.ErrIdentityNotFound {
+		user, err = u.provisionUser(ctx, claims, roles)
+	}
+	if err != nil {
+		return claims, nil, nil, err
+	}
+
+	err = user.CheckActive()
+	if err != nil {
+		return claims, user, nil, err
+	}
+
+	// Second factors are checked by the provider, which must confirm one where the role requires it
+	if u.mfaUseCase.IsRequired(user.Role) && !u.provedMFA(claims) {
+		return claims, user, nil, domain.ErrOIDCMFAMissing
+	}
+
+	response, err := startSession(ctx, u.tokenManager, u.authRepo, user, client)
+	return claims, user, response, err
+}
+
+// provedMFA checks if the amr or acr claim shows that the provider checked a second factor
+func (u *oidcUseCase) provedMFA(claims *security.OIDCClaims) bool {
+	for _, method := range claims.AMR {
+		if slices.Contains(u.config.MFAMethods, method) {
+			return true
+		}
+	}
+	return claims.ACR != "" && slices.Contains(u.config.MFAContexts, claims.ACR)
+}
+
+// findUser returns the user linked to the provider account
+func (u *oidcUseCase) findUser(ctx context.Context, claims *security.OIDCClaims, roles []domain.UserRole) (*domain.User, error) {
+	identity, err := u.oidcRepo.GetIdentity(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inactive users are rejected by the caller with a specific error
+	user, err := u.userRepo.GetByIDIncludingDeactivated(ctx, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.oidcRepo.TouchIdentity(ctx, claims.Issuer, claims.Subject, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.config.SyncRoles {
+		err = u.syncRoles(ctx, user, roles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// provisionUser creates the user on its first login.
+// An existing local account with the same username is never taken over.
+func (u *oidcUseCase) provisionUser(ctx context.Context, claims *security.OIDCClaims, roles []domain.UserRole) (*domain.User, error) {
+	username := claims.PreferredUsername
+	if username == "" {
+		username = claims.Email
+	}
+	if username == "" || utf8.RuneCountInString(username) > domain.MaxUsernameLength {
+		return nil, domain.ErrOIDCUsernameMissing
+	}
+
+	fullname := claims.Name
+	if fullname == "" {
+		fullname = username
+	}
+
+	// No password, the user logs in through the identity provider only
+	user := &domain.User{
+		ID:       "user-" + uuid.New().String(),
+		Username: username,
+		Fullname: fullname,
+		Role:     roles[0],
+		Status:   domain.UserStatusActive,
+	}
+
+	err := u.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.linkUser(ctx, user, claims, roles)
+	if err != nil {
+		// Do not leave a user behind that no one can log in as
+		if deleteErr := u.userRepo.Delete(ctx, user.ID); deleteErr != nil {
+			log.Printf("Failed to remove partially provisioned user '%s': %v\n", user.ID, deleteErr)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *oidcUseCase) linkUser(ctx context.Context, user *domain.User, claims *security.OIDCClaims, roles []domain.UserRole) error {
+	if len(roles) > 1 {
+		err := u.roleRepo.SetUserRoles(ctx, user.ID, roles)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	return u.oidcRepo.CreateIdentity(ctx, &domain.ExternalIdentity{
+		Issuer:      claims.Issuer,
+		Subject:     claims.Subject,
+		UserID:      user.ID,
+		Email:       claims.Email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	})
+}
+
+// syncRoles replaces the roles of user when the provider groups changed
+func (u *oidcUseCase) syncRoles(ctx context.Context, user *domain.User, roles []domain.UserRole) error {
+	current, err := u.roleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == roles[0] && sameRoles(current, roles) {
+		return nil
+	}
+
+	err = u.roleRepo.SetUserRoles(ctx, user.ID, roles)
+	if err != nil {
+		return err
+	}
+
+	user.Role = roles[0]
+	u.principals.Invalidate(user.ID)
+
+	return nil
+}
+
+// mapRoles returns the roles granted by groups, the default role when none matches
+func (u *oidcUseCase) mapRoles(groups []string) []domain.UserRole {
+	var roles []domain.UserRole
+	for _, mapping := range u.config.GroupRoles {
+		if slices.Contains(groups, mapping.Group) && !slices.Contains(roles, mapping.Role) {
+			roles = append(roles, mapping.Role)
+		}
+	}
+
+	if len(roles) == 0 {
+		roles = []domain.UserRole{u.config.DefaultRole}
+	}
+
+	return roles
+}
+
+// sameRoles reports whether a and b hold the same roles in any order
+func sameRoles(a, b []domain.UserRole) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, role := range a {
+		if !slices.Contains(b, role) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxPasswordLength bounds the work of hashing a password
+const maxPasswordLength = 128
+
+// Password policy violation codes
+const (
+	PasswordTooShort         = "too_short"
+	PasswordTooLong          = "too_long"
+	PasswordTooSimple        = "too_simple"
+	PasswordContainsUsername = "contains_username"
+	PasswordReused           = "reused"
+	PasswordBreached         = "breached"
+)
+
+// PasswordPolicy decides which new passwords are accepted
+type PasswordPolicy interface {
+	// Check returns a *domain.ValidationError for field listing every rule password breaks.
+	// The history is only checked when user already exists.
+	Check(ctx context.Context, field string, user *domain.User, password string) error
+	// Remember adds the hash of a newly set password to the history of the user
+	Remember(ctx context.Context, userID, passwordHash string)
+}
+
+// PasswordPolicyConfig configures the password policy
+type PasswordPolicyConfig struct {
+	MinLength int
+	// MinCharClasses is how many of lowercase letters, uppercase letters, digits and symbols are required
+	MinCharClasses int
+	// HistorySize is how many previous passwords cannot be reused, zero disables the history
+	HistorySize int
+}
+
+type passwordPolicy struct {
+	historyRepo  repository.PasswordHistoryRepository
+	passwordHash security.PasswordHash
+	breached     security.BreachedPasswords
+	config       PasswordPolicyConfig
+}
+
+// NewPasswordPolicy creates a new password policy
+func NewPasswordPolicy(
+	historyRepo repository.PasswordHistoryRepository,
+	passwordHash security.PasswordHash,
+	breached security.BreachedPasswords,
+	config PasswordPolicyConfig,
+) PasswordPolicy {
+	return &passwordPolicy{
+		historyRepo:  historyRepo,
+		passwordHash: passwordHash,
+		breached:     breached,
+		config:       config,
+	}
+}
+
+func (p *passwordPolicy) Check(ctx context.Context, field string, user *domain.User, password string) error {
+	var violations []domain.FieldError
+	violate := func(code, message string) {
+		violations = append(violations, domain.FieldError{Field: field, Code: code, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		violate(PasswordTooShort, fmt.Sprintf("Password must be at least %d characters long", p.config.MinLength))
+	}
+	if length > maxPasswordLength {
+		violate(PasswordTooLong, fmt.Sprintf("Password must be at most %d characters long", maxPasswordLength))
+	}
+
+	if charClasses(password) < p.config.MinCharClasses {
+		violate(PasswordTooSimple, fmt.Sprintf(
+			"Password must contain at least %d of lowercase letters, uppercase letters, digits and symbols",
+			p.config.MinCharClasses))
+	}
+
+	// Very short usernames would match by accident
+	if len(user.Username) >= 3 && strings.Contains(strings.ToLower(password), strings.ToLower(user.Username)) {
+		violate(PasswordContainsUsername, "Password must not contain the username")
+	}
+
+	breached, err := p.breached.Contains(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		violate(PasswordBreached, "Password appears in a list of breached passwords, choose another one")
+	}
+
+	if user.ID != "" && p.config.HistorySize > 0 {
+		reused, err := p.isReused(ctx, user, password)
+		if err != nil {
+			return err
+		}
+		if reused {
+			violate(PasswordReused, fmt.Sprintf("Password must differ from the last %d passwords", p.config.HistorySize))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &domain.ValidationError{Errors: violations}
+	}
+
+	return nil
+}
+
+// isReused compares password with the current password and the history of user
+func (p *passwordPolicy) isReused(ctx context.Context, user *domain.User, password string) (bool, error) {
+	// Users created before the history existed only have their current password
+	if user.Password != "" && p.passwordHash.Compare(user.Password, password) == nil {
+		return true, nil
+	}
+
+	entries, err := p.historyRepo.ListRecent(ctx, user.ID, p.config.HistorySize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		if p.passwordHash.Compare(entry.PasswordHash, password) == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (p *passwordPolicy) Remember(ctx context.Context, userID, passwordHash string) {
+	if p.config.HistorySize <= 0 {
+		return
+	}
+
+	// The password is already changed, so a lost entry only weakens the reuse check
+	err := p.historyRepo.Add(ctx, &domain.PasswordHistoryEntry{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	}, p.config.HistorySize)
+	if err != nil {
+		log.Printf("Failed to record password history of user '%s': %v\n", userID, err)
+	}
+}
+
+// charClasses counts the character classes used in password
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
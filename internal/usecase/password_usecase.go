@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/notification"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordUseCase defines password management use case interface
+type PasswordUseCase interface {
+	ChangePassword(ctx context.Context, userID string, req *ChangePasswordRequest) error
+	RequestReset(ctx context.Context, username, requestedBy string) error
+	ResetPassword(ctx context.Context, req *ResetPasswordRequest) error
+}
+
+// ChangePasswordRequest represents self-service password change request
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ResetPasswordRequest represents password reset request
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// PasswordResetConfig configures password reset tokens
+type PasswordResetConfig struct {
+	// URL is the page the reset link points to, the token is appended as query parameter
+	URL      string
+	TokenAge time.Duration
+}
+
+type passwordUseCase struct {
+	userRepo       repository.UserRepository
+	authRepo       repository.AuthenticationRepository
+	resetRepo      repository.PasswordResetRepository
+	passwordHash   security.PasswordHash
+	passwordPolicy PasswordPolicy
+	notifier       notification.Notifier
+	revocations    security.RevocationStore
+	config         PasswordResetConfig
+}
+
+// NewPasswordUseCase creates a new password use case
+func NewPasswordUseCase(
+	userRepo repository.UserRepository,
+	authRepo repository.AuthenticationRepository,
+	resetRepo repository.PasswordResetRepository,
+	passwordHash security.PasswordHash,
+	passwordPolicy PasswordPolicy,
+	notifier notification.Notifier,
+	revocations security.RevocationStore,
+	config PasswordResetConfig,
+) PasswordUseCase {
+	return &passwordUseCase{
+		userRepo:       userRepo,
+		authRepo:       authRepo,
+		resetRepo:      resetRepo,
+		passwordHash:   passwordHash,
+		passwordPolicy: passwordPolicy,
+		notifier:       notifier,
+		revocations:    revocations,
+		config:         config,
+	}
+}
+
+func (u *passwordUseCase) ChangePassword(ctx context.Context, userID string, req *ChangePasswordRequest) error {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Verify old password
+	err = u.passwordHash.Compare(user.Password, req.OldPassword)
+	if err != nil {
+		return domain.ErrPasswordMismatch
+	}
+
+	return u.setPassword(ctx, user, req.NewPassword)
+}
+
+func (u *passwordUseCase) RequestReset(ctx context.Context, username, requestedBy string) error {
+	user, err := u.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	token, err := security.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	// Only the latest reset link stays valid
+	err = u.resetRepo.DeleteByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	reset := &domain.PasswordReset{
+		ID:        "reset-" + uuid.New().String(),
+		UserID:    user.ID,
+		TokenHash: security.HashToken(token),
+		CreatedBy: requestedBy,
+		ExpiresAt: time.Now().Add(u.config.TokenAge),
+	}
+
+	err = u.resetRepo.Create(ctx, reset)
+	if err != nil {
+		return err
+	}
+
+	return u.notifier.SendPasswordReset(ctx, user, linkWithToken(u.config.URL, token), reset.ExpiresAt)
+}
+
+func (u *passwordUseCase) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	// Consume token, it cannot be used again even if the update below fails
+	reset, err := u.resetRepo.ConsumeToken(ctx, security.HashToken(req.Token))
+	if err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = u.setPassword(ctx, user, req.NewPassword)
+
+	// Let the user retry with a password the policy accepts
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		if releaseErr := u.resetRepo.Release(ctx, reset.ID); releaseErr != nil {
+			return releaseErr
+		}
+	}
+
+	return err
+}
+
+// setPassword stores a new password accepted by the policy and revokes every token of the user
+func (u *passwordUseCase) setPassword(ctx context.Context, user *domain.User, password string) error {
+	err := u.passwordPolicy.Check(ctx, "new_password", user, password)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.passwordHash.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	err = u.userRepo.Update(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	u.passwordPolicy.Remember(ctx, user.ID, hashedPassword)
+
+	err = u.authRepo.DeleteTokensByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	u.revocations.RevokeUser(user.ID)
+
+	return nil
+}
+
+// linkWithToken appends token as query parameter to the page at base
+func linkWithToken(base, token string) string {
+	link, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
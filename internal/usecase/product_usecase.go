@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultProductPageSize = 20
+	maxProductPageSize     = 100
+)
+
+// ProductUseCase defines product catalog use case interface
+type ProductUseCase interface {
+	List(ctx context.Context, req *ListProductsRequest) (*ProductListResponse, error)
+	Get(ctx context.Context, id string) (*domain.Product, error)
+	Create(ctx context.Context, req *CreateProductRequest) (*domain.Product, error)
+	Update(ctx context.Context, id string, req *UpdateProductRequest) (*domain.Product, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ListProductsRequest represents product list query
+type ListProductsRequest struct {
+	Search     string
+	CategoryID string
+	// IncludeSubcategories also lists the products of every category below CategoryID
+	IncludeSubcategories bool
+	UnitOfMeasure        string
+	Active               *bool
+	Page                 int
+	PageSize             int
+}
+
+// ProductListResponse represents one page of products
+type ProductListResponse struct {
+	Products   []*domain.Product `json:"products"`
+	Pagination Pagination        `json:"pagination"`
+}
+
+// CreateProductRequest represents product creation request, products are active unless Active is false
+type CreateProductRequest struct {
+	SKU           string  `json:"sku"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	CategoryID    string  `json:"category_id"`
+	UnitOfMeasure string  `json:"unit_of_measure"`
+	Barcode       string  `json:"barcode"`
+	Cost          float64 `json:"cost"`
+	Active        *bool   `json:"active"`
+}
+
+// UpdateProductRequest represents product update request, omitted fields are left unchanged
+type UpdateProductRequest struct {
+	SKU           *string  `json:"sku"`
+	Name          *string  `json:"name"`
+	Description   *string  `json:"description"`
+	CategoryID    *string  `json:"category_id"` // Empty to remove the product from its category
+	UnitOfMeasure *string  `json:"unit_of_measure"`
+	Barcode       *string  `json:"barcode"`
+	Cost          *float64 `json:"cost"`
+	Active        *bool    `json:"active"`
+}
+
+type productUseCase struct {
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	uomRepo      repository.UoMRepository
+	stockRepo    repository.StockRepository
+}
+
+// NewProductUseCase creates a new product catalog use case
+func NewProductUseCase(
+	productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository,
+	uomRepo repository.UoMRepository,
+	stockRepo repository.StockRepository,
+) ProductUseCase {
+	return &productUseCase{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		uomRepo:      uomRepo,
+		stockRepo:    stockRepo,
+	}
+}
+
+func (u *productUseCase) List(ctx context.Context, req *ListProductsRequest) (*ProductListResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = defaultProductPageSize
+	}
+	if pageSize > maxProductPageSize {
+		pageSize = maxProductPageSize
+	}
+
+	products, total, err := u.productRepo.List(ctx, repository.ProductFilter{
+		Search:               strings.TrimSpace(req.Search),
+		CategoryID:           strings.TrimSpace(req.CategoryID),
+		IncludeSubcategories: req.IncludeSubcategories,
+		UnitOfMeasure:        normalizeCode(req.UnitOfMeasure),
+		Active:               req.Active,
+		Limit:                pageSize,
+		Offset:               (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductListResponse{
+		Products: products,
+		Pagination: Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (u *productUseCase) Get(ctx context.Context, id string) (*domain.Product, error) {
+	return u.productRepo.GetByID(ctx, id)
+}
+
+func (u *productUseCase) Create(ctx context.Context, req *CreateProductRequest) (*domain.Product, error) {
+	now := time.Now()
+	product := &domain.Product{
+		ID:            "product-" + uuid.New().String(),
+		SKU:           normalizeCode(req.SKU),
+		Name:          strings.TrimSpace(req.Name),
+		Description:   strings.TrimSpace(req.Description),
+		CategoryID:    strings.TrimSpace(req.CategoryID),
+		UnitOfMeasure: normalizeCode(req.UnitOfMeasure),
+		Barcode:       strings.TrimSpace(req.Barcode),
+		Cost:          req.Cost,
+		Active:        req.Active == nil || *req.Active,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err := u.validate(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.productRepo.Create(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (u *productUseCase) Update(ctx context.Context, id string, req *UpdateProductRequest) (*domain.Product, error) {
+	product, err := u.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SKU != nil {
+		product.SKU = normalizeCode(*req.SKU)
+	}
+	if req.Name != nil {
+		product.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		product.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.CategoryID != nil {
+		product.CategoryID = strings.TrimSpace(*req.CategoryID)
+	}
+	if req.UnitOfMeasure != nil && normalizeCode(*req.UnitOfMeasure) != product.UnitOfMeasure {
+		// The factors of the other units are relative to the base unit
+		units, err := u.uomRepo.ListProductUnits(ctx, product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(units) > 0 {
+			return nil, &domain.ValidationError{Errors: []domain.FieldError{{
+				Field:   "unit_of_measure",
+				Code:    "in_use",
+				Message: "The base unit cannot change while other units of the product are defined",
+			}}}
+		}
+		// Stock is kept in the base unit
+		moved, err := u.stockRepo.HasMovements(ctx, product.ID)
+		if err != nil {
+			return nil, err
+		}
+		if moved {
+			return nil, &domain.ValidationError{Errors: []domain.FieldError{{
+				Field:   "unit_of_measure",
+				Code:    "in_use",
+				Message: "The base unit cannot change once stock of the product was posted",
+			}}}
+		}
+		product.UnitOfMeasure = normalizeCode(*req.UnitOfMeasure)
+	}
+	if req.Barcode != nil {
+		product.Barcode = strings.TrimSpace(*req.Barcode)
+	}
+	if req.Cost != nil {
+		product.Cost = *req.Cost
+	}
+	if req.Active != nil {
+		product.Active = *req.Active
+	}
+
+	err = u.validate(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	product.UpdatedAt = time.Now()
+	err = u.productRepo.Update(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Delete removes the product. Deactivate products that are still referenced instead.
+func (u *productUseCase) Delete(ctx context.Context, id string) error {
+	return u.productRepo.Delete(ctx, id)
+}
+
+// validate returns a *domain.ValidationError listing every invalid field of product
+func (u *productUseCase) validate(ctx context.Context, product *domain.Product) error {
+	var violations []domain.FieldError
+	required := func(field, value string, maxLength int) {
+		if value == "" {
+			violations = append(violations, domain.FieldError{Field: field, Code: "required", Message: "Field is required"})
+		}
+		checkLength(&violations, field, value, maxLength)
+	}
+
+	required("sku", product.SKU, 64)
+	required("name", product.Name, 255)
+	required("unit_of_measure", product.UnitOfMeasure, 20)
+	checkLength(&violations, "barcode", product.Barcode, 64)
+
+	if product.Cost < 0 || math.IsNaN(product.Cost) || math.IsInf(product.Cost, 0) {
+		violations = append(violations, domain.FieldError{Field: "cost", Code: "invalid", Message: "Cost must not be negative"})
+	}
+
+	if product.UnitOfMeasure != "" {
+		_, err := u.uomRepo.GetByCode(ctx, product.UnitOfMeasure)
+		if err == domain.ErrUoMNotFound {
+			violations = append(violations, domain.FieldError{Field: "unit_of_measure", Code: "not_found", Message: "Unit of measure not found"})
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if product.CategoryID != "" {
+		_, err := u.categoryRepo.GetByID(ctx, product.CategoryID)
+		if err == domain.ErrCategoryNotFound {
+			violations = append(violations, domain.FieldError{Field: "category_id", Code: "not_found", Message: "Category not found"})
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(violations) > 0 {
+		return &domain.ValidationError{Errors: violations}
+	}
+
+	return nil
+}
+
+// checkLength adds a violation when value is longer than maxLength characters
+func checkLength(violations *[]domain.FieldError, field, value string, maxLength int) {
+	if utf8.RuneCountInString(value) > maxLength {
+		*violations = append(*violations, domain.FieldError{
+			Field:   field,
+			Code:    "too_long",
+			Message: fmt.Sprintf("Field must be at most %d characters long", maxLength),
+		})
+	}
+}
+
+// normalizeCode trims and upper-cases codes like SKUs and units of measure
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"regexp"
+)
+
+// roleNamePattern restricts role names to lowercase identifiers
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,19}$`)
+
+// RoleUseCase defines role management use case interface
+type RoleUseCase interface {
+	ListRoles(ctx context.Context) ([]*domain.Role, error)
+	GetRole(ctx context.Context, name domain.UserRole) (*domain.Role, error)
+	CreateRole(ctx context.Context, req *RoleRequest) (*domain.Role, error)
+	UpdateRole(ctx context.Context, name domain.UserRole, req *RoleRequest) (*domain.Role, error)
+	DeleteRole(ctx context.Context, name domain.UserRole) error
+	ListPermissions(ctx context.Context) ([]*domain.PermissionInfo, error)
+	GetUserRoles(ctx context.Context, userID string) ([]domain.UserRole, error)
+	SetUserRoles(ctx context.Context, userID string, roles []domain.UserRole) error
+}
+
+// RoleRequest represents role create and update request
+type RoleRequest struct {
+	Name        domain.UserRole     `json:"name"`
+	Description string              `json:"description"`
+	Permissions []domain.Permission `json:"permissions"`
+}
+
+type roleUseCase struct {
+	roleRepo   repository.RoleRepository
+	userRepo   repository.UserRepository
+	principals security.PrincipalCache
+}
+
+// NewRoleUseCase creates a new role use case
+func NewRoleUseCase(roleRepo repository.RoleRepository, userRepo repository.UserRepository, principals security.PrincipalCache) RoleUseCase {
+	return &roleUseCase{
+		roleRepo:   roleRepo,
+		userRepo:   userRepo,
+		principals: principals,
+	}
+}
+
+func (u *roleUseCase) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	return u.roleRepo.List(ctx)
+}
+
+func (u *roleUseCase) GetRole(ctx context.Context, name domain.UserRole) (*domain.Role, error) {
+	return u.roleRepo.GetByName(ctx, name)
+}
+
+func (u *roleUseCase) CreateRole(ctx context.Context, req *RoleRequest) (*domain.Role, error) {
+	if !roleNamePattern.MatchString(string(req.Name)) {
+		return nil, domain.ErrBadRequest
+	}
+
+	role := &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	err := u.roleRepo.Create(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.roleRepo.GetByName(ctx, role.Name)
+}
+
+func (u *roleUseCase) UpdateRole(ctx context.Context, name domain.UserRole, req *RoleRequest) (*domain.Role, error) {
+	role := &domain.Role{
+		Name:        name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	err := u.roleRepo.Update(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Permissions of every user holding the role changed
+	u.principals.Clear()
+
+	return u.roleRepo.GetByName(ctx, name)
+}
+
+func (u *roleUseCase) DeleteRole(ctx context.Context, name domain.UserRole) error {
+	err := u.roleRepo.Delete(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	u.principals.Clear()
+
+	return nil
+}
+
+func (u *roleUseCase) ListPermissions(ctx context.Context) ([]*domain.PermissionInfo, error) {
+	return u.roleRepo.ListPermissions(ctx)
+}
+
+func (u *roleUseCase) GetUserRoles(ctx context.Context, userID string) ([]domain.UserRole, error) {
+	// Make sure an unknown user is reported instead of an empty list
+	if _, err := u.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return u.roleRepo.GetUserRoles(ctx, userID)
+}
+
+func (u *roleUseCase) SetUserRoles(ctx context.Context, userID string, roles []domain.UserRole) error {
+	err := u.roleRepo.SetUserRoles(ctx, userID, roles)
+	if err != nil {
+		return err
+	}
+
+	u.principals.Invalidate(userID)
+
+	return nil
+}
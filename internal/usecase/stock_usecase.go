@@ -0,0 +1,545 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultStockPageSize = 50
+	maxStockPageSize     = 200
+	// maxPostingLines bounds the balances a single posting keeps locked
+	maxPostingLines = 500
+)
+
+// StockUseCase defines the stock ledger use case interface. Every change of
+// stock is posted as movements to the append-only ledger, and the balances are
+// updated in the same transaction. Users post to and read the warehouses they
+// may operate.
+type StockUseCase interface {
+	// Post converts the lines of req to the base unit of their products and
+	// applies them to the ledger and the balances, all or nothing
+	Post(ctx context.Context, user *domain.User, req *PostStockRequest) (*domain.StockPosting, error)
+	// Reverse posts movements cancelling every movement of a posting
+	Reverse(ctx context.Context, user *domain.User, postingID string, req *ReverseStockRequest) (*domain.StockPosting, error)
+	ListMovements(ctx context.Context, user *domain.User, req *ListMovementsRequest) (*MovementListResponse, error)
+	ListBalances(ctx context.Context, user *domain.User, req *ListBalancesRequest) (*BalanceListResponse, error)
+}
+
+// PostStockRequest represents a stock posting. The reference names the document
+// that caused it, e.g. a purchase order.
+type PostStockRequest struct {
+	Type          domain.MovementType `json:"type"`
+	ReferenceType string              `json:"reference_type"`
+	ReferenceID   string              `json:"reference_id"`
+	Note          string              `json:"note"`
+	Lines         []StockLineRequest  `json:"lines"`
+}
+
+// StockLineRequest represents a line of a stock posting. Quantity is positive
+// except for adjustments, which are signed, and UoM defaults to the base unit.
+type StockLineRequest struct {
+	ProductID  string         `json:"product_id"`
+	LocationID string         `json:"location_id"`
+	Quantity   domain.Decimal `json:"quantity"`
+	UoM        string         `json:"uom"`
+	// ToLocationID is the destination of transfers, LocationID their source
+	ToLocationID string `json:"to_location_id"`
+}
+
+// ReverseStockRequest represents the reversal of a posting
+type ReverseStockRequest struct {
+	Note string `json:"note"`
+}
+
+// ListMovementsRequest represents stock movement list query
+type ListMovementsRequest struct {
+	ProductID     string
+	LocationID    string
+	WarehouseID   string
+	PostingID     string
+	Type          domain.MovementType
+	ReferenceType string
+	ReferenceID   string
+	From          *time.Time
+	To            *time.Time
+	Page          int
+	PageSize      int
+}
+
+// MovementListResponse represents one page of stock movements
+type MovementListResponse struct {
+	Movements  []*domain.StockMovement `json:"movements"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+// ListBalancesRequest represents stock balance list query
+type ListBalancesRequest struct {
+	ProductID   string
+	LocationID  string
+	WarehouseID string
+	IncludeZero bool
+	Page        int
+	PageSize    int
+}
+
+// BalanceListResponse represents one page of stock balances
+type BalanceListResponse struct {
+	Balances   []*domain.StockBalance `json:"balances"`
+	Pagination Pagination             `json:"pagination"`
+}
+
+type stockUseCase struct {
+	unitOfWork       repository.UnitOfWork
+	stockRepo        repository.StockRepository
+	locationRepo     repository.LocationRepository
+	warehouseUseCase WarehouseUseCase
+	uomUseCase       UoMUseCase
+}
+
+// NewStockUseCase creates a new stock use case
+func NewStockUseCase(
+	unitOfWork repository.UnitOfWork,
+	stockRepo repository.StockRepository,
+	locationRepo repository.LocationRepository,
+	warehouseUseCase WarehouseUseCase,
+	uomUseCase UoMUseCase,
+) StockUseCase {
+	return &stockUseCase{
+		unitOfWork:       unitOfWork,
+		stockRepo:        stockRepo,
+		locationRepo:     locationRepo,
+		warehouseUseCase: warehouseUseCase,
+		uomUseCase:       uomUseCase,
+	}
+}
+
+// postingMovement is a movement to apply and the request line it comes from
+type postingMovement struct {
+	movement *domain.StockMovement
+	line     int
+}
+
+func (u *stockUseCase) Post(ctx context.Context, user *domain.User, req *PostStockRequest) (*domain.StockPosting, error) {
+	referenceType := strings.TrimSpace(req.ReferenceType)
+	referenceID := strings.TrimSpace(req.ReferenceID)
+	note := strings.TrimSpace(req.Note)
+
+	var violations []domain.FieldError
+	if !req.Type.IsValid() {
+		violations = append(violations, domain.FieldError{
+			Field:   "type",
+			Code:    "invalid",
+			Message: "Type must be receipt, issue, adjustment or transfer",
+		})
+	}
+	checkLength(&violations, "reference_type", referenceType, 50)
+	checkLength(&violations, "reference_id", referenceID, 100)
+	checkLength(&violations, "note", note, 500)
+	if len(req.Lines) == 0 {
+		violations = append(violations, domain.FieldError{Field: "lines", Code: "required", Message: "At least one line is required"})
+	}
+	if len(req.Lines) > maxPostingLines {
+		violations = append(violations, domain.FieldError{
+			Field:   "lines",
+			Code:    "too_long",
+			Message: fmt.Sprintf("A posting holds at most %d lines", maxPostingLines),
+		})
+	}
+
+	if len(violations) > 0 {
+		return nil, &domain.ValidationError{Errors: violations}
+	}
+
+	posting := &domain.StockPosting{ID: "posting-" + uuid.New().String()}
+	now := time.Now()
+
+	var movements []postingMovement
+	for i, line := range req.Lines {
+		lineMovements, lineViolations, err := u.lineMovements(ctx, user, req.Type, i, line)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, lineViolations...)
+
+		for _, movement := range lineMovements {
+			movement.ID = "movement-" + uuid.New().String()
+			movement.PostingID = posting.ID
+			movement.Type = req.Type
+			movement.ReferenceType = referenceType
+			movement.ReferenceID = referenceID
+			movement.Note = note
+			setMovementActor(movement, user)
+			movement.CreatedAt = now
+			movements = append(movements, postingMovement{movement: movement, line: i})
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, &domain.ValidationError{Errors: violations}
+	}
+
+	line, err := u.apply(ctx, posting, movements)
+	switch err {
+	case domain.ErrInsufficientStock:
+		return nil, &domain.ValidationError{Errors: []domain.FieldError{{
+			Field:   fmt.Sprintf("lines[%d].quantity", line),
+			Code:    "insufficient_stock",
+			Message: "Not enough stock of the product at the location",
+		}}}
+	case domain.ErrQuantityOutOfRange:
+		return nil, &domain.ValidationError{Errors: []domain.FieldError{{
+			Field:   fmt.Sprintf("lines[%d].quantity", line),
+			Code:    "out_of_range",
+			Message: fmt.Sprintf("Quantities and balances hold at most %d integer digits", domain.DecimalIntegerDigits),
+		}}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return posting, nil
+}
+
+// lineMovements returns the movements of line i, two for transfers and one otherwise,
+// or the violations of the line. Locations outside the warehouses of user return
+// domain.ErrWarehouseNotAssigned.
+func (u *stockUseCase) lineMovements(
+	ctx context.Context,
+	user *domain.User,
+	movementType domain.MovementType,
+	i int,
+	line StockLineRequest,
+) ([]*domain.StockMovement, []domain.FieldError, error) {
+	field := func(name string) string {
+		return fmt.Sprintf("lines[%d].%s", i, name)
+	}
+
+	var violations []domain.FieldError
+	violate := func(name, code, message string) {
+		violations = append(violations, domain.FieldError{Field: field(name), Code: code, Message: message})
+	}
+
+	productID := strings.TrimSpace(line.ProductID)
+	if productID == "" {
+		violate("product_id", "required", "Field is required")
+	}
+
+	switch {
+	case line.Quantity.IsZero():
+		violate("quantity", "required", "Quantity must not be zero")
+	case line.Quantity.Sign() < 0 && movementType != domain.MovementAdjustment:
+		violate("quantity", "invalid", "Quantity must be positive, only adjustments are signed")
+	}
+
+	source, err := u.postingLocation(ctx, user, strings.TrimSpace(line.LocationID), field("location_id"), &violations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var destination *domain.Location
+	toLocationID := strings.TrimSpace(line.ToLocationID)
+	if movementType == domain.MovementTransfer {
+		destination, err = u.postingLocation(ctx, user, toLocationID, field("to_location_id"), &violations)
+		if err != nil {
+			return nil, nil, err
+		}
+		if destination != nil && source != nil && destination.ID == source.ID {
+			violate("to_location_id", "invalid", "Transfers need two different locations")
+		}
+	} else if toLocationID != "" {
+		violate("to_location_id", "invalid", "Only transfers have a destination location")
+	}
+
+	if productID == "" || line.Quantity.IsZero() {
+		return nil, violations, nil
+	}
+
+	quantity, err := u.uomUseCase.Normalize(ctx, productID, line.Quantity, line.UoM)
+	var validationErr *domain.ValidationError
+	switch {
+	case err == domain.ErrProductNotFound:
+		violate("product_id", "not_found", "Product not found")
+		return nil, violations, nil
+	case errors.As(err, &validationErr):
+		for _, violation := range validationErr.Errors {
+			violation.Field = field(violation.Field)
+			violations = append(violations, violation)
+		}
+		return nil, violations, nil
+	case err != nil:
+		return nil, nil, err
+	}
+
+	if quantity.BaseQuantity.IsZero() {
+		violate("quantity", "precision", fmt.Sprintf("%s %s rounds to 0 %s", quantity.Quantity, quantity.UoM, quantity.BaseUoM))
+	}
+
+	if len(violations) > 0 {
+		return nil, violations, nil
+	}
+
+	movement := func(location *domain.Location, negate bool) *domain.StockMovement {
+		movement := &domain.StockMovement{
+			ProductID:       productID,
+			LocationID:      location.ID,
+			WarehouseID:     location.WarehouseID,
+			Quantity:        quantity.BaseQuantity,
+			UoM:             quantity.BaseUoM,
+			EnteredQuantity: quantity.Quantity,
+			EnteredUoM:      quantity.UoM,
+		}
+		if negate {
+			movement.Quantity = movement.Quantity.Neg()
+			movement.EnteredQuantity = movement.EnteredQuantity.Neg()
+		}
+		return movement
+	}
+
+	switch movementType {
+	case domain.MovementIssue:
+		return []*domain.StockMovement{movement(source, true)}, nil, nil
+	case domain.MovementTransfer:
+		return []*domain.StockMovement{movement(source, true), movement(destination, false)}, nil, nil
+	}
+	return []*domain.StockMovement{movement(source, false)}, nil, nil
+}
+
+// postingLocation returns the location id of a posting line, or nil after adding a
+// violation of field to violations when it is missing, unknown or inactive, or its warehouse is inactive
+func (u *stockUseCase) postingLocation(
+	ctx context.Context,
+	user *domain.User,
+	id string,
+	field string,
+	violations *[]domain.FieldError,
+) (*domain.Location, error) {
+	if id == "" {
+		*violations = append(*violations, domain.FieldError{Field: field, Code: "required", Message: "Field is required"})
+		return nil, nil
+	}
+
+	location, err := u.locationRepo.GetByID(ctx, id)
+	if err == domain.ErrLocationNotFound {
+		*violations = append(*violations, domain.FieldError{Field: field, Code: "not_found", Message: "Location not found"})
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := u.warehouseUseCase.CanOperate(ctx, user, location.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, domain.ErrWarehouseNotAssigned
+	}
+
+	if !location.Active {
+		*violations = append(*violations, domain.FieldError{Field: field, Code: "inactive", Message: "Location is inactive"})
+		return nil, nil
+	}
+
+	// Holders of warehouse:manage may operate inactive warehouses, but stock is not posted to them
+	warehouse, err := u.warehouseUseCase.Get(ctx, location.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if !warehouse.Active {
+		*violations = append(*violations, domain.FieldError{Field: field, Code: "inactive", Message: "Warehouse of the location is inactive"})
+		return nil, nil
+	}
+
+	return location, nil
+}
+
+func (u *stockUseCase) Reverse(ctx context.Context, user *domain.User, postingID string, req *ReverseStockRequest) (*domain.StockPosting, error) {
+	note := strings.TrimSpace(req.Note)
+
+	var violations []domain.FieldError
+	checkLength(&violations, "note", note, 500)
+	if len(violations) > 0 {
+		return nil, &domain.ValidationError{Errors: violations}
+	}
+
+	original, err := u.stockRepo.GetPosting(ctx, postingID)
+	if err != nil {
+		return nil, err
+	}
+
+	posting := &domain.StockPosting{ID: "posting-" + uuid.New().String()}
+	now := time.Now()
+
+	var movements []postingMovement
+	for i, movement := range original.Movements {
+		if movement.ReversesID != "" {
+			return nil, domain.ErrCannotReverseReversal
+		}
+
+		allowed, err := u.warehouseUseCase.CanOperate(ctx, user, movement.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, domain.ErrWarehouseNotAssigned
+		}
+
+		reversal := &domain.StockMovement{
+			ID:              "movement-" + uuid.New().String(),
+			PostingID:       posting.ID,
+			ProductID:       movement.ProductID,
+			LocationID:      movement.LocationID,
+			WarehouseID:     movement.WarehouseID,
+			Quantity:        movement.Quantity.Neg(),
+			UoM:             movement.UoM,
+			EnteredQuantity: movement.EnteredQuantity.Neg(),
+			EnteredUoM:      movement.EnteredUoM,
+			Type:            movement.Type,
+			ReferenceType:   movement.ReferenceType,
+			ReferenceID:     movement.ReferenceID,
+			Note:            note,
+			ReversesID:      movement.ID,
+			CreatedAt:       now,
+		}
+		setMovementActor(reversal, user)
+		movements = append(movements, postingMovement{line: i, movement: reversal})
+	}
+
+	_, err = u.apply(ctx, posting, movements)
+	if err != nil {
+		return nil, err
+	}
+
+	return posting, nil
+}
+
+// apply applies movements in one transaction and adds them to posting. Balances
+// that would turn negative return domain.ErrInsufficientStock and the line of the movement.
+func (u *stockUseCase) apply(ctx context.Context, posting *domain.StockPosting, movements []postingMovement) (int, error) {
+	// Locking balances in one order keeps concurrent postings from deadlocking
+	sort.SliceStable(movements, func(i, j int) bool {
+		a, b := movements[i].movement, movements[j].movement
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		return a.LocationID < b.LocationID
+	})
+
+	var line int
+	err := u.unitOfWork.Do(ctx, func(repos *repository.TxRepositories) error {
+		for _, m := range movements {
+			line = m.line
+			err := repos.Stock.ApplyMovement(ctx, m.movement)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return line, err
+	}
+
+	for _, m := range movements {
+		posting.Movements = append(posting.Movements, m.movement)
+	}
+
+	return 0, nil
+}
+
+func (u *stockUseCase) ListMovements(ctx context.Context, user *domain.User, req *ListMovementsRequest) (*MovementListResponse, error) {
+	page, pageSize := stockPage(req.Page, req.PageSize)
+
+	filter := repository.MovementFilter{
+		ProductID:     strings.TrimSpace(req.ProductID),
+		LocationID:    strings.TrimSpace(req.LocationID),
+		WarehouseID:   strings.TrimSpace(req.WarehouseID),
+		PostingID:     strings.TrimSpace(req.PostingID),
+		Type:          req.Type,
+		ReferenceType: strings.TrimSpace(req.ReferenceType),
+		ReferenceID:   strings.TrimSpace(req.ReferenceID),
+		From:          req.From,
+		To:            req.To,
+		Limit:         pageSize,
+		Offset:        (page - 1) * pageSize,
+	}
+	if !user.HasPermission(domain.PermissionWarehouseManage) {
+		filter.UserID = user.ID
+	}
+
+	movements, total, err := u.stockRepo.ListMovements(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MovementListResponse{
+		Movements: movements,
+		Pagination: Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+func (u *stockUseCase) ListBalances(ctx context.Context, user *domain.User, req *ListBalancesRequest) (*BalanceListResponse, error) {
+	page, pageSize := stockPage(req.Page, req.PageSize)
+
+	filter := repository.BalanceFilter{
+		ProductID:   strings.TrimSpace(req.ProductID),
+		LocationID:  strings.TrimSpace(req.LocationID),
+		WarehouseID: strings.TrimSpace(req.WarehouseID),
+		IncludeZero: req.IncludeZero,
+		Limit:       pageSize,
+		Offset:      (page - 1) * pageSize,
+	}
+	if !user.HasPermission(domain.PermissionWarehouseManage) {
+		filter.UserID = user.ID
+	}
+
+	balances, total, err := u.stockRepo.ListBalances(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BalanceListResponse{
+		Balances: balances,
+		Pagination: Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// stockPage returns the page and page size of a stock list query
+func stockPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultStockPageSize
+	}
+	if pageSize > maxStockPageSize {
+		pageSize = maxStockPageSize
+	}
+	return page, pageSize
+}
+
+// setMovementActor records the principal posting a movement, which is either a user or an API key
+func setMovementActor(movement *domain.StockMovement, user *domain.User) {
+	if user.APIKeyID != "" {
+		movement.APIKeyID = user.APIKeyID
+		return
+	}
+	movement.UserID = user.ID
+}
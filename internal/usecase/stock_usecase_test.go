@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"testing"
+)
+
+// The fakes embed the interfaces they stand in for, so calling a method the
+// tests do not expect panics.
+
+type fakeWarehouseRepository struct {
+	repository.WarehouseRepository
+	warehouses map[string]*domain.Warehouse
+	// assigned holds the user and API key IDs assigned to each warehouse
+	assigned map[string][]string
+}
+
+func (r *fakeWarehouseRepository) GetByID(ctx context.Context, id string) (*domain.Warehouse, error) {
+	warehouse, ok := r.warehouses[id]
+	if !ok {
+		return nil, domain.ErrWarehouseNotFound
+	}
+	return warehouse, nil
+}
+
+func (r *fakeWarehouseRepository) IsAssigned(ctx context.Context, userID, warehouseID string) (bool, error) {
+	warehouse, ok := r.warehouses[warehouseID]
+	if !ok || !warehouse.Active {
+		return false, nil
+	}
+	for _, id := range r.assigned[warehouseID] {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeLocationRepository struct {
+	repository.LocationRepository
+	locations map[string]*domain.Location
+}
+
+func (r *fakeLocationRepository) GetByID(ctx context.Context, id string) (*domain.Location, error) {
+	location, ok := r.locations[id]
+	if !ok {
+		return nil, domain.ErrLocationNotFound
+	}
+	return location, nil
+}
+
+// fakeUoMUseCase treats every quantity as already being in the base unit
+type fakeUoMUseCase struct {
+	UoMUseCase
+}
+
+func (u *fakeUoMUseCase) Normalize(ctx context.Context, productID string, quantity domain.Decimal, uom string) (*domain.NormalizedQuantity, error) {
+	return &domain.NormalizedQuantity{
+		ProductID:    productID,
+		Quantity:     quantity,
+		UoM:          "pcs",
+		Factor:       domain.NewDecimal(1),
+		BaseQuantity: quantity,
+		BaseUoM:      "pcs",
+	}, nil
+}
+
+type fakeStockRepository struct {
+	repository.StockRepository
+	applied []*domain.StockMovement
+}
+
+func (r *fakeStockRepository) ApplyMovement(ctx context.Context, movement *domain.StockMovement) error {
+	r.applied = append(r.applied, movement)
+	return nil
+}
+
+type fakeUnitOfWork struct {
+	stockRepo *fakeStockRepository
+}
+
+func (u *fakeUnitOfWork) Do(ctx context.Context, fn func(repos *repository.TxRepositories) error) error {
+	return fn(&repository.TxRepositories{Stock: u.stockRepo})
+}
+
+func newTestStockUseCase(assigned map[string][]string) (StockUseCase, *fakeStockRepository) {
+	warehouseRepo := &fakeWarehouseRepository{
+		warehouses: map[string]*domain.Warehouse{
+			"warehouse-1": {ID: "warehouse-1", Code: "MAIN", Active: true},
+		},
+		assigned: assigned,
+	}
+	locationRepo := &fakeLocationRepository{
+		locations: map[string]*domain.Location{
+			"location-1": {ID: "location-1", WarehouseID: "warehouse-1", Code: "A-01", Active: true},
+		},
+	}
+	stockRepo := &fakeStockRepository{}
+
+	stockUseCase := NewStockUseCase(
+		&fakeUnitOfWork{stockRepo: stockRepo},
+		stockRepo,
+		locationRepo,
+		NewWarehouseUseCase(warehouseRepo, locationRepo, nil, nil),
+		&fakeUoMUseCase{},
+	)
+	return stockUseCase, stockRepo
+}
+
+func receiptRequest() *PostStockRequest {
+	return &PostStockRequest{
+		Type: domain.MovementReceipt,
+		Lines: []StockLineRequest{
+			{ProductID: "product-1", LocationID: "location-1", Quantity: domain.NewDecimal(5)},
+		},
+	}
+}
+
+func TestStockPostWithAPIKey(t *testing.T) {
+	key := &domain.APIKey{ID: "apikey-1", Name: "scanner", Prefix: "sk_test", Role: domain.UserRole("operator")}
+	principal := key.Principal([]domain.Permission{domain.PermissionStockPost})
+
+	t.Run("assigned key is recorded as the actor", func(t *testing.T) {
+		stockUseCase, stockRepo := newTestStockUseCase(map[string][]string{"warehouse-1": {key.ID}})
+
+		posting, err := stockUseCase.Post(context.Background(), principal, receiptRequest())
+		if err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+		if len(posting.Movements) != 1 || len(stockRepo.applied) != 1 {
+			t.Fatalf("Post() posted %d movements and applied %d, want 1", len(posting.Movements), len(stockRepo.applied))
+		}
+
+		movement := stockRepo.applied[0]
+		if movement.APIKeyID != key.ID {
+			t.Errorf("movement APIKeyID = %q, want %q", movement.APIKeyID, key.ID)
+		}
+		if movement.UserID != "" {
+			t.Errorf("movement UserID = %q, want empty for API keys", movement.UserID)
+		}
+	})
+
+	t.Run("unassigned key is refused", func(t *testing.T) {
+		stockUseCase, stockRepo := newTestStockUseCase(map[string][]string{"warehouse-1": {"user-1"}})
+
+		_, err := stockUseCase.Post(context.Background(), principal, receiptRequest())
+		if err != domain.ErrWarehouseNotAssigned {
+			t.Fatalf("Post() error = %v, want %v", err, domain.ErrWarehouseNotAssigned)
+		}
+		if len(stockRepo.applied) != 0 {
+			t.Errorf("Post() applied %d movements, want 0", len(stockRepo.applied))
+		}
+	})
+}
+
+func TestStockPostWithUser(t *testing.T) {
+	user := &domain.User{ID: "user-1", Username: "operator", Status: domain.UserStatusActive}
+	stockUseCase, stockRepo := newTestStockUseCase(map[string][]string{"warehouse-1": {user.ID}})
+
+	_, err := stockUseCase.Post(context.Background(), user, receiptRequest())
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	movement := stockRepo.applied[0]
+	if movement.UserID != user.ID || movement.APIKeyID != "" {
+		t.Errorf("movement actor = (%q, %q), want (%q, \"\")", movement.UserID, movement.APIKeyID, user.ID)
+	}
+}
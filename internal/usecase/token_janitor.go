@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"api-stockflow/internal/repository"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// TokenJanitor periodically deletes expired and revoked refresh tokens
+type TokenJanitor interface {
+	// Run cleans up every interval until ctx is cancelled
+	Run(ctx context.Context)
+	// Cleanup deletes stale tokens in batches until none are left
+	Cleanup(ctx context.Context) error
+	Metrics() TokenCleanupMetrics
+}
+
+// defaultTokenCleanupBatchSize is used when no batch size is configured
+const defaultTokenCleanupBatchSize = 1000
+
+// TokenJanitorConfig configures the refresh token cleanup
+type TokenJanitorConfig struct {
+	Interval  time.Duration // Time between two cleanups, zero or less disables the janitor
+	BatchSize int           // Tokens deleted per statement, keeps locks and transactions short
+}
+
+// TokenCleanupMetrics reports the work done by the token janitor of this instance since startup
+type TokenCleanupMetrics struct {
+	Enabled         bool       `json:"enabled"`
+	IntervalSeconds int64      `json:"interval_seconds"`
+	BatchSize       int        `json:"batch_size"`
+	Runs            int64      `json:"runs"`
+	FailedRuns      int64      `json:"failed_runs"`
+	DeletedExpired  int64      `json:"deleted_expired"`
+	DeletedRevoked  int64      `json:"deleted_revoked"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+	LastDurationMs  int64      `json:"last_duration_ms"`
+	LastDeleted     int64      `json:"last_deleted"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+type tokenJanitor struct {
+	authRepo repository.AuthenticationRepository
+	config   TokenJanitorConfig
+
+	mu      sync.Mutex
+	metrics TokenCleanupMetrics
+}
+
+// NewTokenJanitor creates a new refresh token janitor
+func NewTokenJanitor(authRepo repository.AuthenticationRepository, config TokenJanitorConfig) TokenJanitor {
+	if config.BatchSize < 1 {
+		config.BatchSize = defaultTokenCleanupBatchSize
+	}
+
+	return &tokenJanitor{
+		authRepo: authRepo,
+		config:   config,
+		metrics: TokenCleanupMetrics{
+			Enabled:         config.Interval > 0,
+			IntervalSeconds: int64(config.Interval / time.Second),
+			BatchSize:       config.BatchSize,
+		},
+	}
+}
+
+func (j *tokenJanitor) Run(ctx context.Context) {
+	if j.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Cleanup(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to clean up refresh tokens: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *tokenJanitor) Cleanup(ctx context.Context) error {
+	start := time.Now()
+
+	var expired, revoked int64
+	var err error
+	for ctx.Err() == nil {
+		var count *repository.StaleTokenCount
+		count, err = j.authRepo.DeleteStaleTokens(ctx, time.Now(), j.config.BatchSize)
+		if err != nil {
+			break
+		}
+
+		expired += count.Expired
+		revoked += count.Revoked
+
+		// A short batch means nothing is left
+		if count.Expired+count.Revoked < int64(j.config.BatchSize) {
+			break
+		}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.metrics.Runs++
+	j.metrics.DeletedExpired += expired
+	j.metrics.DeletedRevoked += revoked
+	j.metrics.LastRunAt = &start
+	j.metrics.LastDurationMs = time.Since(start).Milliseconds()
+	j.metrics.LastDeleted = expired + revoked
+	j.metrics.LastError = ""
+	if err != nil {
+		j.metrics.FailedRuns++
+		j.metrics.LastError = err.Error()
+	}
+
+	return err
+}
+
+func (j *tokenJanitor) Metrics() TokenCleanupMetrics {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	metrics := j.metrics
+	if metrics.LastRunAt != nil {
+		lastRunAt := *metrics.LastRunAt
+		metrics.LastRunAt = &lastRunAt
+	}
+
+	return metrics
+}
@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UoMUseCase defines the units of measure master and the conversions of products.
+// Stock-affecting use cases call Normalize to turn a quantity in any unit of a
+// product into its base unit.
+type UoMUseCase interface {
+	ListUnits(ctx context.Context) ([]*domain.UnitOfMeasure, error)
+	GetUnit(ctx context.Context, code string) (*domain.UnitOfMeasure, error)
+	CreateUnit(ctx context.Context, req *CreateUoMRequest) (*domain.UnitOfMeasure, error)
+	UpdateUnit(ctx context.Context, code string, req *UpdateUoMRequest) (*domain.UnitOfMeasure, error)
+	DeleteUnit(ctx context.Context, code string) error
+
+	// ListProductUnits returns the base unit of the product followed by its other units
+	ListProductUnits(ctx context.Context, productID string) ([]*domain.ProductUnit, error)
+	SetProductUnit(ctx context.Context, productID, uom string, req *SetProductUnitRequest) (*domain.ProductUnit, error)
+	DeleteProductUnit(ctx context.Context, productID, uom string) error
+
+	// Normalize converts quantity in uom to the base unit of the product, an empty
+	// uom is the base unit. Unknown conversions and quantities the base unit cannot
+	// hold without rounding, when its rule refuses rounding, return a *domain.ValidationError.
+	Normalize(ctx context.Context, productID string, quantity domain.Decimal, uom string) (*domain.NormalizedQuantity, error)
+}
+
+// CreateUoMRequest represents unit of measure creation request.
+// Decimals defaults to 0 and Rounding to reject.
+type CreateUoMRequest struct {
+	Code     string              `json:"code"`
+	Name     string              `json:"name"`
+	Decimals int                 `json:"decimals"`
+	Rounding domain.RoundingRule `json:"rounding"`
+}
+
+// UpdateUoMRequest represents unit of measure update request, omitted fields are left unchanged
+type UpdateUoMRequest struct {
+	Name     *string              `json:"name"`
+	Decimals *int                 `json:"decimals"`
+	Rounding *domain.RoundingRule `json:"rounding"`
+}
+
+// SetProductUnitRequest represents the number of base units in one unit
+type SetProductUnitRequest struct {
+	Factor domain.Decimal `json:"factor"`
+}
+
+// ConvertQuantityRequest represents a quantity to normalize to the base unit
+type ConvertQuantityRequest struct {
+	Quantity domain.Decimal `json:"quantity"`
+	UoM      string         `json:"uom"`
+}
+
+type uomUseCase struct {
+	uomRepo     repository.UoMRepository
+	productRepo repository.ProductRepository
+}
+
+// NewUoMUseCase creates a new unit of measure use case
+func NewUoMUseCase(uomRepo repository.UoMRepository, productRepo repository.ProductRepository) UoMUseCase {
+	return &uomUseCase{
+		uomRepo:     uomRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (u *uomUseCase) ListUnits(ctx context.Context) ([]*domain.UnitOfMeasure, error) {
+	return u.uomRepo.List(ctx)
+}
+
+func (u *uomUseCase) GetUnit(ctx context.Context, code string) (*domain.UnitOfMeasure, error) {
+	return u.uomRepo.GetByCode(ctx, normalizeCode(code))
+}
+
+func (u *uomUseCase) CreateUnit(ctx context.Context, req *CreateUoMRequest) (*domain.UnitOfMeasure, error) {
+	rounding := req.Rounding
+	if rounding == "" {
+		rounding = domain.RoundingReject
+	}
+
+	now := time.Now()
+	unit := &domain.UnitOfMeasure{
+		Code:      normalizeCode(req.Code),
+		Name:      strings.TrimSpace(req.Name),
+		Decimals:  req.Decimals,
+		Rounding:  rounding,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err := validateUoM(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.uomRepo.Create(ctx, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return unit, nil
+}
+
+func (u *uomUseCase) UpdateUnit(ctx context.Context, code string, req *UpdateUoMRequest) (*domain.UnitOfMeasure, error) {
+	unit, err := u.uomRepo.GetByCode(ctx, normalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		unit.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Decimals != nil {
+		unit.Decimals = *req.Decimals
+	}
+	if req.Rounding != nil {
+		unit.Rounding = *req.Rounding
+	}
+
+	err = validateUoM(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	unit.UpdatedAt = time.Now()
+	err = u.uomRepo.Update(ctx, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return unit, nil
+}
+
+// DeleteUnit removes a unit no product uses
+func (u *uomUseCase) DeleteUnit(ctx context.Context, code string) error {
+	return u.uomRepo.Delete(ctx, normalizeCode(code))
+}
+
+func (u *uomUseCase) ListProductUnits(ctx context.Context, productID string) ([]*domain.ProductUnit, error) {
+	product, err := u.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := u.uomRepo.ListProductUnits(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &domain.ProductUnit{
+		ProductID: product.ID,
+		UoM:       product.UnitOfMeasure,
+		Factor:    domain.NewDecimal(1),
+		IsBase:    true,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+	}
+
+	return append([]*domain.ProductUnit{base}, units...), nil
+}
+
+func (u *uomUseCase) SetProductUnit(ctx context.Context, productID, uom string, req *SetProductUnitRequest) (*domain.ProductUnit, error) {
+	uom = normalizeCode(uom)
+
+	product, err := u.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []domain.FieldError
+	if uom == product.UnitOfMeasure {
+		violations = append(violations, domain.FieldError{Field: "uom", Code: "base_unit", Message: "The factor of the base unit is always 1"})
+	} else if _, err := u.uomRepo.GetByCode(ctx, uom); err == domain.ErrUoMNotFound {
+		violations = append(violations, domain.FieldError{Field: "uom", Code: "not_found", Message: "Unit of measure not found"})
+	} else if err != nil {
+		return nil, err
+	}
+	if req.Factor.Sign() <= 0 {
+		violations = append(violations, domain.FieldError{Field: "factor", Code: "invalid", Message: "Factor must be greater than 0"})
+	}
+	if len(violations) > 0 {
+		return nil, &domain.ValidationError{Errors: violations}
+	}
+
+	now := time.Now()
+	err = u.uomRepo.SetProductUnit(ctx, &domain.ProductUnit{
+		ProductID: product.ID,
+		UoM:       uom,
+		Factor:    req.Factor,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.uomRepo.GetProductUnit(ctx, product.ID, uom)
+}
+
+func (u *uomUseCase) DeleteProductUnit(ctx context.Context, productID, uom string) error {
+	return u.uomRepo.DeleteProductUnit(ctx, productID, normalizeCode(uom))
+}
+
+func (u *uomUseCase) Normalize(ctx context.Context, productID string, quantity domain.Decimal, uom string) (*domain.NormalizedQuantity, error) {
+	uom = normalizeCode(uom)
+
+	product, err := u.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if uom == "" {
+		uom = product.UnitOfMeasure
+	}
+
+	// Quantities in the base unit are still checked against its decimals
+	factor := domain.NewDecimal(1)
+	if uom != product.UnitOfMeasure {
+		unit, err := u.uomRepo.GetProductUnit(ctx, product.ID, uom)
+		if err == domain.ErrProductUnitNotFound {
+			return nil, &domain.ValidationError{Errors: []domain.FieldError{{
+				Field:   "uom",
+				Code:    "unknown_conversion",
+				Message: fmt.Sprintf("No conversion from %s to %s is defined for the product", uom, product.UnitOfMeasure),
+			}}}
+		}
+		if err != nil {
+			return nil, err
+		}
+		factor = unit.Factor
+	}
+
+	base, err := u.uomRepo.GetByCode(ctx, product.UnitOfMeasure)
+	if err != nil {
+		return nil, err
+	}
+
+	baseQuantity, rounded := quantity.MulRound(factor, base.Decimals, base.Rounding)
+	if rounded && base.Rounding == domain.RoundingReject {
+		exact, _ := quantity.MulRound(factor, domain.DecimalPlaces, base.Rounding)
+		return nil, &domain.ValidationError{Errors: []domain.FieldError{{
+			Field: "quantity",
+			Code:  "precision",
+			Message: fmt.Sprintf("%s %s is %s %s, which allows %d decimal places",
+				quantity, uom, exact, base.Code, base.Decimals),
+		}}}
+	}
+
+	return &domain.NormalizedQuantity{
+		ProductID:    product.ID,
+		Quantity:     quantity,
+		UoM:          uom,
+		Factor:       factor,
+		BaseQuantity: baseQuantity,
+		BaseUoM:      base.Code,
+		Rounded:      rounded,
+	}, nil
+}
+
+// validateUoM returns a *domain.ValidationError listing every invalid field of unit
+func validateUoM(unit *domain.UnitOfMeasure) error {
+	var violations []domain.FieldError
+	if unit.Code == "" {
+		violations = append(violations, domain.FieldError{Field: "code", Code: "required", Message: "Field is required"})
+	}
+	checkLength(&violations, "code", unit.Code, 20)
+	if unit.Name == "" {
+		violations = append(violations, domain.FieldError{Field: "name", Code: "required", Message: "Field is required"})
+	}
+	checkLength(&violations, "name", unit.Name, 100)
+
+	if unit.Decimals < 0 || unit.Decimals > domain.DecimalPlaces {
+		violations = append(violations, domain.FieldError{
+			Field:   "decimals",
+			Code:    "invalid",
+			Message: fmt.Sprintf("Decimals must be between 0 and %d", domain.DecimalPlaces),
+		})
+	}
+	if !unit.Rounding.IsValid() {
+		violations = append(violations, domain.FieldError{
+			Field:   "rounding",
+			Code:    "invalid",
+			Message: "Rounding must be reject, half_up, up or down",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &domain.ValidationError{Errors: violations}
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"api-stockflow/internal/security"
+	"context"
+	"strings"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// UserUseCase defines admin user management use case interface.
+// Every change revokes all sessions of the changed user.
+type UserUseCase interface {
+	List(ctx context.Context, req *ListUsersRequest) (*UserListResponse, error)
+	Get(ctx context.Context, id string) (*domain.User, error)
+	Update(ctx context.Context, actorID, id string, req *UpdateUserRequest) (*domain.User, error)
+	SetPassword(ctx context.Context, id, password string) error
+	ChangeStatus(ctx context.Context, actorID, id string, req *ChangeUserStatusRequest) (*domain.User, error)
+	Delete(ctx context.Context, actorID, id string) error
+}
+
+// ListUsersRequest represents user list query
+type ListUsersRequest struct {
+	Search   string
+	Role     domain.UserRole
+	Status   domain.UserStatus
+	Page     int
+	PageSize int
+}
+
+// UserListResponse represents one page of users
+type UserListResponse struct {
+	Users      []*domain.User `json:"users"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// Pagination describes the returned page
+type Pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+	Total    int `json:"total"`
+}
+
+// ChangeUserStatusRequest represents account status change request
+type ChangeUserStatusRequest struct {
+	Status domain.UserStatus `json:"-"`
+	Reason string            `json:"reason"`
+}
+
+// UpdateUserRequest represents admin user update request, omitted fields are left unchanged
+type UpdateUserRequest struct {
+	Fullname *string          `json:"fullname"`
+	Role     *domain.UserRole `json:"role"`
+}
+
+type userUseCase struct {
+	userRepo       repository.UserRepository
+	authRepo       repository.AuthenticationRepository
+	roleRepo       repository.RoleRepository
+	passwordHash   security.PasswordHash
+	passwordPolicy PasswordPolicy
+	revocations    security.RevocationStore
+	principals     security.PrincipalCache
+}
+
+// NewUserUseCase creates a new user management use case
+func NewUserUseCase(
+	userRepo repository.UserRepository,
+	authRepo repository.AuthenticationRepository,
+	roleRepo repository.RoleRepository,
+	passwordHash security.PasswordHash,
+	passwordPolicy PasswordPolicy,
+	revocations security.RevocationStore,
+	principals security.PrincipalCache,
+) UserUseCase {
+	return &userUseCase{
+		userRepo:       userRepo,
+		authRepo:       authRepo,
+		roleRepo:       roleRepo,
+		passwordHash:   passwordHash,
+		passwordPolicy: passwordPolicy,
+		revocations:    revocations,
+		principals:     principals,
+	}
+}
+
+func (u *userUseCase) List(ctx context.Context, req *ListUsersRequest) (*UserListResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = defaultUserPageSize
+	}
+	if pageSize > maxUserPageSize {
+		pageSize = maxUserPageSize
+	}
+
+	users, total, err := u.userRepo.List(ctx, repository.UserFilter{
+		Search: strings.TrimSpace(req.Search),
+		Role:   req.Role,
+		Status: req.Status,
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserListResponse{
+		Users: users,
+		Pagination: Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// Get returns a user in any status
+func (u *userUseCase) Get(ctx context.Context, id string) (*domain.User, error) {
+	user, err := u.userRepo.GetByIDIncludingDeactivated(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Roles, err = u.roleRepo.GetUserRoles(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *userUseCase) Update(ctx context.Context, actorID, id string, req *UpdateUserRequest) (*domain.User, error) {
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Fullname != nil {
+		fullname := strings.TrimSpace(*req.Fullname)
+		if fullname == "" {
+			return nil, domain.ErrBadRequest
+		}
+		user.Fullname = fullname
+	}
+
+	roleChanged := req.Role != nil && *req.Role != user.Role
+	if roleChanged {
+		// An admin must not lock themselves out by accident
+		if actorID == id {
+			return nil, domain.ErrCannotManageSelf
+		}
+		user.Role = *req.Role
+	}
+
+	err = u.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other changes keep the sessions, the next request loads the updated principal
+	if roleChanged {
+		err = u.endSessions(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		u.principals.Invalidate(id)
+	}
+
+	return u.Get(ctx, id)
+}
+
+func (u *userUseCase) SetPassword(ctx context.Context, id, password string) error {
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = u.passwordPolicy.Check(ctx, "new_password", user, password)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := u.passwordHash.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	err = u.userRepo.Update(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	u.passwordPolicy.Remember(ctx, user.ID, hashedPassword)
+
+	return u.endSessions(ctx, id)
+}
+
+// ChangeStatus suspends, deactivates or reactivates an account
+func (u *userUseCase) ChangeStatus(ctx context.Context, actorID, id string, req *ChangeUserStatusRequest) (*domain.User, error) {
+	if actorID == id {
+		return nil, domain.ErrCannotManageSelf
+	}
+
+	user, err := u.userRepo.GetByIDIncludingDeactivated(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.CanTransitionTo(req.Status) {
+		return nil, domain.ErrInvalidStatusChange
+	}
+
+	err = u.userRepo.SetStatus(ctx, id, req.Status, strings.TrimSpace(req.Reason))
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.endSessions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Get(ctx, id)
+}
+
+// Delete removes the user; its sessions are removed with it.
+// Deactivate users referenced by other records instead.
+func (u *userUseCase) Delete(ctx context.Context, actorID, id string) error {
+	if actorID == id {
+		return domain.ErrCannotManageSelf
+	}
+
+	err := u.userRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	u.revocations.RevokeUser(id)
+	u.principals.Invalidate(id)
+
+	return nil
+}
+
+// endSessions revokes every refresh and access token of the user and
+// drops the cached principal so the change applies to the next request
+func (u *userUseCase) endSessions(ctx context.Context, id string) error {
+	err := u.authRepo.DeleteTokensByUserID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	u.revocations.RevokeUser(id)
+	u.principals.Invalidate(id)
+
+	return nil
+}
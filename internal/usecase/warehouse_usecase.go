@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"api-stockflow/internal/domain"
+	"api-stockflow/internal/repository"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseUseCase defines warehouse and warehouse assignment use case interface.
+// Users operate the warehouses they are assigned to; holders of warehouse:manage operate every warehouse.
+type WarehouseUseCase interface {
+	// List returns the warehouses user may operate
+	List(ctx context.Context, user *domain.User, req *ListWarehousesRequest) ([]*domain.Warehouse, error)
+	Get(ctx context.Context, id string) (*domain.Warehouse, error)
+	Create(ctx context.Context, req *CreateWarehouseRequest) (*domain.Warehouse, error)
+	Update(ctx context.Context, id string, req *UpdateWarehouseRequest) (*domain.Warehouse, error)
+	// Delete removes a warehouse without locations
+	Delete(ctx context.Context, id string) error
+
+	// ListUsers returns the users assigned to the warehouse
+	ListUsers(ctx context.Context, warehouseID string) ([]*domain.User, error)
+	GetUserWarehouses(ctx context.Context, userID string) ([]*domain.Warehouse, error)
+	// SetUserWarehouses replaces the warehouses assigned to the user
+	SetUserWarehouses(ctx context.Context, userID string, req *SetUserWarehousesRequest) ([]*domain.Warehouse, error)
+	GetAPIKeyWarehouses(ctx context.Context, apiKeyID string) ([]*domain.Warehouse, error)
+	// SetAPIKeyWarehouses replaces the warehouses assigned to the API key
+	SetAPIKeyWarehouses(ctx context.Context, apiKeyID string, req *SetUserWarehousesRequest) ([]*domain.Warehouse, error)
+
+	// CanOperate checks if user may operate the warehouse. Only holders of
+	// warehouse:manage operate inactive warehouses.
+	CanOperate(ctx context.Context, user *domain.User, warehouseID string) (bool, error)
+	// LocationWarehouse returns the ID of the warehouse holding the location
+	LocationWarehouse(ctx context.Context, locationID string) (string, error)
+}
+
+// ListWarehousesRequest represents warehouse list query
+type ListWarehousesRequest struct {
+	Search string
+	Active *bool
+}
+
+// CreateWarehouseRequest represents warehouse creation request, warehouses are active unless Active is false
+type CreateWarehouseRequest struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Active  *bool  `json:"active"`
+}
+
+// UpdateWarehouseRequest represents warehouse update request, omitted fields are left unchanged
+type UpdateWarehouseRequest struct {
+	Code    *string `json:"code"`
+	Name    *string `json:"name"`
+	Address *string `json:"address"`
+	Active  *bool   `json:"active"`
+}
+
+// SetUserWarehousesRequest represents the warehouses assigned to a user or API key, an empty list removes every assignment
+type SetUserWarehousesRequest struct {
+	WarehouseIDs []string `json:"warehouse_ids"`
+}
+
+type warehouseUseCase struct {
+	warehouseRepo repository.WarehouseRepository
+	locationRepo  repository.LocationRepository
+	userRepo      repository.UserRepository
+	apiKeyRepo    repository.APIKeyRepository
+}
+
+// NewWarehouseUseCase creates a new warehouse use case
+func NewWarehouseUseCase(
+	warehouseRepo repository.WarehouseRepository,
+	locationRepo repository.LocationRepository,
+	userRepo repository.UserRepository,
+	apiKeyRepo repository.APIKeyRepository,
+) WarehouseUseCase {
+	return &warehouseUseCase{
+		warehouseRepo: warehouseRepo,
+		locationRepo:  locationRepo,
+		userRepo:      userRepo,
+		apiKeyRepo:    apiKeyRepo,
+	}
+}
+
+func (u *warehouseUseCase) List(ctx context.Context, user *domain.User, req *ListWarehousesRequest) ([]*domain.Warehouse, error) {
+	filter := repository.WarehouseFilter{
+		Search: strings.TrimSpace(req.Search),
+		Active: req.Active,
+	}
+	// Inactive warehouses cannot be operated
+	if !user.HasPermission(domain.PermissionWarehouseManage) {
+		active := true
+		filter.UserID = user.ID
+		filter.Active = &active
+	}
+
+	return u.warehouseRepo.List(ctx, filter)
+}
+
+func (u *warehouseUseCase) Get(ctx context.Context, id string) (*domain.Warehouse, error) {
+	return u.warehouseRepo.GetByID(ctx, id)
+}
+
+func (u *warehouseUseCase) Create(ctx context.Context, req *CreateWarehouseRequest) (*domain.Warehouse, error) {
+	now := time.Now()
+	warehouse := &domain.Warehouse{
+		ID:        "warehouse-" + uuid.New().String(),
+		Code:      normalizeCode(req.Code),
+		Name:      strings.TrimSpace(req.Name),
+		Address:   strings.TrimSpace(req.Address),
+		Active:    req.Active == nil || *req.Active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err := validateWarehouse(warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.warehouseRepo.Create(ctx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+func (u *warehouseUseCase) Update(ctx context.Context, id string, req *UpdateWarehouseRequest) (*domain.Warehouse, error) {
+	warehouse, err := u.warehouseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code != nil {
+		warehouse.Code = normalizeCode(*req.Code)
+	}
+	if req.Name != nil {
+		warehouse.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Address != nil {
+		warehouse.Address = strings.TrimSpace(*req.Address)
+	}
+	if req.Active != nil {
+		warehouse.Active = *req.Active
+	}
+
+	err = validateWarehouse(warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouse.UpdatedAt = time.Now()
+	err = u.warehouseRepo.Update(ctx, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+func (u *warehouseUseCase) Delete(ctx context.Context, id string) error {
+	return u.warehouseRepo.Delete(ctx, id)
+}
+
+func (u *warehouseUseCase) ListUsers(ctx context.Context, warehouseID string) ([]*domain.User, error) {
+	_, err := u.warehouseRepo.GetByID(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.warehouseRepo.ListUsers(ctx, warehouseID)
+}
+
+func (u *warehouseUseCase) GetUserWarehouses(ctx context.Context, userID string) ([]*domain.Warehouse, error) {
+	_, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.warehouseRepo.List(ctx, repository.WarehouseFilter{UserID: userID})
+}
+
+func (u *warehouseUseCase) SetUserWarehouses(ctx context.Context, userID string, req *SetUserWarehousesRequest) ([]*domain.Warehouse, error) {
+	_, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = assignmentError(u.warehouseRepo.SetUserWarehouses(ctx, userID, uniqueIDs(req.WarehouseIDs)))
+	if err != nil {
+		return nil, err
+	}
+
+	return u.warehouseRepo.List(ctx, repository.WarehouseFilter{UserID: userID})
+}
+
+func (u *warehouseUseCase) GetAPIKeyWarehouses(ctx context.Context, apiKeyID string) ([]*domain.Warehouse, error) {
+	_, err := u.apiKeyRepo.GetByID(ctx, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.warehouseRepo.List(ctx, repository.WarehouseFilter{UserID: apiKeyID})
+}
+
+func (u *warehouseUseCase) SetAPIKeyWarehouses(ctx context.Context, apiKeyID string, req *SetUserWarehousesRequest) ([]*domain.Warehouse, error) {
+	_, err := u.apiKeyRepo.GetByID(ctx, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = assignmentError(u.warehouseRepo.SetAPIKeyWarehouses(ctx, apiKeyID, uniqueIDs(req.WarehouseIDs)))
+	if err != nil {
+		return nil, err
+	}
+
+	return u.warehouseRepo.List(ctx, repository.WarehouseFilter{UserID: apiKeyID})
+}
+
+func (u *warehouseUseCase) CanOperate(ctx context.Context, user *domain.User, warehouseID string) (bool, error) {
+	if user.HasPermission(domain.PermissionWarehouseManage) {
+		return true, nil
+	}
+
+	return u.warehouseRepo.IsAssigned(ctx, user.ID, warehouseID)
+}
+
+func (u *warehouseUseCase) LocationWarehouse(ctx context.Context, locationID string) (string, error) {
+	location, err := u.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return "", err
+	}
+
+	return location.WarehouseID, nil
+}
+
+// uniqueIDs returns the trimmed, non-empty IDs without duplicates
+func uniqueIDs(ids []string) []string {
+	var unique []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// assignmentError reports unknown warehouses of an assignment as a *domain.ValidationError
+func assignmentError(err error) error {
+	if err == domain.ErrWarehouseNotFound {
+		return &domain.ValidationError{Errors: []domain.FieldError{
+			{Field: "warehouse_ids", Code: "not_found", Message: "Warehouse not found"},
+		}}
+	}
+	return err
+}
+
+// validateWarehouse returns a *domain.ValidationError listing every invalid field of warehouse
+func validateWarehouse(warehouse *domain.Warehouse) error {
+	var violations []domain.FieldError
+	if warehouse.Code == "" {
+		violations = append(violations, domain.FieldError{Field: "code", Code: "required", Message: "Field is required"})
+	}
+	checkLength(&violations, "code", warehouse.Code, 20)
+	if warehouse.Name == "" {
+		violations = append(violations, domain.FieldError{Field: "name", Code: "required", Message: "Field is required"})
+	}
+	checkLength(&violations, "name", warehouse.Name, 100)
+	checkLength(&violations, "address", warehouse.Address, 500)
+
+	if len(violations) > 0 {
+		return &domain.ValidationError{Errors: violations}
+	}
+
+	return nil
+}